@@ -0,0 +1,79 @@
+package job
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WithAdaptiveConcurrency 自适应限制同时执行的任务数：从 min 开始，任务成功结束且剩余时间充足时逐个放开空位，最多到 max
+// 剩余时间充足指上下文没有截止时间，或剩余时间超过已成功任务平均耗时的 2 倍；任务出错、panic 时不放开，
+// 并发一旦放开不再收回。这是启发式的策略，适合耗时波动大、又不希望一开始就全量并发的任务
+// 设置后覆盖 WithMaxConcurrency，排队超时等行为与之相同；min < 1 按 1 处理，max < min 时按 min 处理
+func WithAdaptiveConcurrency(min, max int) Option {
+	if min < 1 {
+		min = 1
+	}
+	if max < min {
+		max = min
+	}
+	return adaptiveConcurrencyOption{min: min, max: max}
+}
+
+type adaptiveConcurrencyOption struct {
+	min, max int
+}
+
+func (a adaptiveConcurrencyOption) bind(o *options) {
+	o.AdaptiveConcurrency = a
+}
+
+// adaptiveLimit 自适应并发的控制器
+// 信号量容量为 max，预先放入 max-min 个保留占位，放开空位即取走一个保留占位
+type adaptiveLimit struct {
+	sem chan struct{}
+
+	mu        sync.Mutex
+	reserved  int           // 剩余的保留占位
+	succeeded int           // 成功结束的任务数
+	elapsed   time.Duration // 成功任务的总耗时
+}
+
+func newAdaptiveLimit(opt adaptiveConcurrencyOption) (chan struct{}, *adaptiveLimit) {
+	sem := make(chan struct{}, opt.max)
+	for i := opt.min; i < opt.max; i++ {
+		sem <- struct{}{}
+	}
+	return sem, &adaptiveLimit{sem: sem, reserved: opt.max - opt.min}
+}
+
+// adapt 任务执行结束后调用，按结果和剩余时间决定是否放开一个空位
+func (ex *execution) adapt(ctx context.Context, err error, d time.Duration) {
+	a := ex.adaptive
+	if a == nil || err != nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.succeeded++
+	a.elapsed += d
+	if a.reserved == 0 {
+		return
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		avg := a.elapsed / time.Duration(a.succeeded)
+		if time.Until(deadline) <= 2*avg {
+			return
+		}
+	}
+	a.reserved--
+	<-a.sem // 保留占位一定还在信号量中，不会阻塞
+}
+
+// limit 当前允许同时执行的任务数
+func (a *adaptiveLimit) limit() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return cap(a.sem) - a.reserved
+}
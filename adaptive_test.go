@@ -0,0 +1,66 @@
+package job
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptiveConcurrency(t *testing.T) {
+	as := assert.New(t)
+
+	var running, peak int32
+	task := func() (interface{}, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil, nil
+	}
+
+	tg := NewTaskGroup("adaptive", WithDuration(5*time.Second), WithAdaptiveConcurrency(1, 3))
+	for i := 0; i < 12; i++ {
+		tg.AddTaskFunc(task)
+	}
+	_, err := tg.Execute()
+	as.NoError(err)
+	as.Equal(3, tg.cur.adaptive.limit()) // 剩余时间充足，放开到上限
+	as.LessOrEqual(atomic.LoadInt32(&peak), int32(3))
+	as.Greater(atomic.LoadInt32(&peak), int32(1))
+}
+
+func TestAdaptiveConcurrencyHold(t *testing.T) {
+	as := assert.New(t)
+
+	// 任务出错不放开
+	failing := NewTaskGroup("adaptive_err", WithDuration(time.Second), WithAdaptiveConcurrency(1, 3))
+	for i := 0; i < 3; i++ {
+		failing.AddTaskFunc(func() (interface{}, error) { return nil, errors.New("overloaded") })
+	}
+	_, err := failing.Execute()
+	as.NoError(err)
+	as.Equal(1, failing.cur.adaptive.limit())
+
+	// 剩余时间不足平均耗时的 2 倍时不放开
+	slow := NewTaskGroup("adaptive_slow", WithDuration(150*time.Millisecond), WithAdaptiveConcurrency(1, 3),
+		WithLog(&memLog{}))
+	for i := 0; i < 3; i++ {
+		slow.AddTaskFunc(func() (interface{}, error) {
+			time.Sleep(60 * time.Millisecond)
+			return nil, nil
+		})
+	}
+	_, err = slow.Execute()
+	as.NoError(err)
+	as.Equal(1, slow.cur.adaptive.limit())
+
+	as.Equal(adaptiveConcurrencyOption{min: 1, max: 1}, WithAdaptiveConcurrency(0, -1))
+}
@@ -0,0 +1,65 @@
+package job
+
+import "time"
+
+// ExecuteAsync 异步执行所有任务并立即返回，不等待任务结束，配置错误时返回错误
+// 与未设置等待时长的 ExecChan 相同，任务结果仍走超时处理；同时保留结果，可稍后用 CollectPending 取回
+// 设置了 WithDuration 时任务上下文仍在到期后取消
+func (tg *Group) ExecuteAsync() error {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+
+	if len(tg.tasks) == 0 && tg.allowEmpty {
+		tg.runEarlyDefers()
+		return nil
+	}
+	if err := tg.check(); err != nil {
+		tg.runEarlyDefers()
+		return err
+	}
+
+	ex := tg.newExecution(tg.ctx)
+	ex.async = true
+	tg.run(ex)
+	return nil
+}
+
+// CollectPending 最多等待 timeout 让最近一次异步执行的任务全部结束，返回上次取回之后新结束的任务结果
+// complete 为 false 表示超时时仍有任务未结束，可再次调用取回剩余结果；timeout <= 0 时不等待
+// 最近一次执行不是异步执行时返回 nil, true
+func (tg *Group) CollectPending(timeout time.Duration) (results []Result, complete bool) {
+	tg.mu.Lock()
+	ex := tg.cur
+	tg.mu.Unlock()
+
+	if ex == nil || !ex.async {
+		return nil, true
+	}
+
+	select {
+	case <-ex.done:
+		complete = true
+	default:
+		if timeout > 0 {
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+			select {
+			case <-ex.done:
+				complete = true
+			case <-timer.C:
+			}
+		}
+	}
+
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+	results, ex.pending = ex.pending, nil
+	return results, complete
+}
+
+// keepPending 保留异步执行的任务结果供 CollectPending 取回
+func (ex *execution) keepPending(ret Result) {
+	ex.mu.Lock()
+	ex.pending = append(ex.pending, ret)
+	ex.mu.Unlock()
+}
@@ -0,0 +1,59 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectPending(t *testing.T) {
+	as := assert.New(t)
+
+	release := make(chan struct{})
+	tg := NewTaskGroup("collect_pending")
+	tg.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+	tg.AddTaskFunc(func() (interface{}, error) {
+		<-release
+		return 2, nil
+	})
+
+	results, complete := tg.CollectPending(0)
+	as.Nil(results) // 没有执行过
+	as.True(complete)
+
+	as.NoError(tg.ExecuteAsync())
+
+	results, complete = tg.CollectPending(50 * time.Millisecond)
+	as.False(complete)
+	if as.Len(results, 1) {
+		as.Equal(1, results[0].Value)
+	}
+
+	close(release)
+	results, complete = tg.CollectPending(time.Second)
+	as.True(complete)
+	if as.Len(results, 1) { // 已取回的结果不再返回
+		as.Equal(2, results[0].Value)
+	}
+
+	results, complete = tg.CollectPending(0)
+	as.Empty(results)
+	as.True(complete)
+}
+
+func TestCollectPendingNotAsync(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("collect_pending_sync", WithDuration(time.Second), WithCollectRet())
+	tg.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+	_, err := tg.Execute()
+	as.NoError(err)
+
+	results, complete := tg.CollectPending(time.Second)
+	as.Nil(results)
+	as.True(complete)
+
+	empty := NewTaskGroup("collect_pending_empty")
+	as.ErrorIs(empty.ExecuteAsync(), ErrNoTasks)
+}
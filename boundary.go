@@ -0,0 +1,97 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// BoundaryPolicy 任务在截止时间前后完成、结果与截止同时就绪时的取舍
+type BoundaryPolicy int
+
+const (
+	// PreferTimeout 结果交付前上下文已截止就走超时处理（默认）
+	PreferTimeout BoundaryPolicy = iota
+	// PreferComplete 任务在截止时间之前（含）完成时，只要组仍在收集结果，就照常交付而不走超时处理
+	PreferComplete
+)
+
+// WithBoundaryPolicy 设置结果恰好在截止时刻到达时的取舍，默认 PreferTimeout
+// PreferComplete 只对截止（context.DeadlineExceeded）生效，取消时仍走超时处理；完成时间取自 WithClock
+func WithBoundaryPolicy(p BoundaryPolicy) Option {
+	return boundaryPolicyOption(p)
+}
+
+type boundaryPolicyOption BoundaryPolicy
+
+func (b boundaryPolicyOption) bind(o *options) {
+	o.BoundaryPolicy = BoundaryPolicy(b)
+}
+
+// Clock 时间来源，用于判断任务是否在截止时间前完成，测试中可替换为固定时间
+type Clock interface {
+	Now() time.Time
+}
+
+// WithClock 设置判断任务完成时间的时钟，默认为系统时钟；不影响等待时长的计时
+func WithClock(c Clock) Option {
+	return clockOption{c}
+}
+
+type clockOption struct {
+	Clock
+}
+
+func (c clockOption) bind(o *options) {
+	o.Clock = c.Clock
+}
+
+// now 按配置的时钟返回当前时间
+func (ex *execution) now() time.Time {
+	if ex.timeSource == nil {
+		return time.Now()
+	}
+	return ex.timeSource.Now()
+}
+
+// deadline 任务的截止时间，组上下文由计时器取消时取计时器的截止时间
+func (ex *execution) deadline(ctx context.Context) (time.Time, bool) {
+	if d, ok := ctx.Deadline(); ok {
+		return d, true
+	}
+	if ex.clock == nil {
+		return time.Time{}, false
+	}
+	ex.clock.mu.Lock()
+	defer ex.clock.mu.Unlock()
+	return ex.clock.deadline, !ex.clock.deadline.IsZero()
+}
+
+// deliverAtBoundary PreferComplete 时把截止前完成的结果交给仍在收集的组，返回是否已交付
+func (ex *execution) deliverAtBoundary(ctx context.Context, i int, ret Result, finishedAt time.Time) bool {
+	if ex.boundary != PreferComplete || !errors.Is(context.Cause(ctx), context.DeadlineExceeded) {
+		return false
+	}
+	if deadline, ok := ex.deadline(ctx); !ok || finishedAt.After(deadline) {
+		return false
+	}
+
+	ex.sealMu.Lock()
+	defer ex.sealMu.Unlock()
+	if ex.sealed {
+		return false
+	}
+	select {
+	case ex.retChan <- ex.output(i, ret):
+		return true
+	default:
+		return false // 结果通道已满
+	}
+}
+
+// seal 收集方停止等待后调用，此后截止时刻的结果不再交付；之前交付的结果都已进入结果通道
+func (ex *execution) seal() {
+	ex.sealMu.Lock()
+	ex.sealed = true
+	ex.sealMu.Unlock()
+}
@@ -0,0 +1,53 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fixedClock 总是返回同一时间的时钟
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func TestBoundaryPolicy(t *testing.T) {
+	as := assert.New(t)
+
+	// 第一个结果的 sink 让收集方在截止后仍在处理，第二个任务在此期间完成，时钟决定它是否算截止前完成
+	run := func(opts ...Option) ([]Result, *slowSt) {
+		late := &slowSt{d: 60 * time.Millisecond, value: 2, handled: make(chan error, 1)}
+		tg := NewTaskGroup("boundary", append(opts, WithDuration(50*time.Millisecond), WithCollectRet(),
+			WithSink(func(r Result) {
+				if r.Value == 1 {
+					time.Sleep(60 * time.Millisecond)
+				}
+			}))...)
+		tg.AddTask(&slowSt{d: 20 * time.Millisecond, value: 1})
+		tg.AddTask(late)
+		results, err := tg.Execute()
+		as.NoError(err)
+		return results, late
+	}
+
+	atDeadline := fixedClock(time.Time{})            // 总在截止时间之前
+	afterDeadline := fixedClock(time.Unix(1<<40, 0)) // 总在截止时间之后
+
+	// 默认优先超时
+	results, late := run(WithClock(atDeadline))
+	as.Equal([]Result{{Value: 1}}, results)
+	as.Len(late.handled, 1)
+
+	// 截止前完成的结果照常交付，不走超时处理
+	results, late = run(WithBoundaryPolicy(PreferComplete), WithClock(atDeadline))
+	as.Equal([]Result{{Value: 1}, {Value: 2}}, results)
+	as.Empty(late.handled)
+
+	// 截止后完成仍走超时处理
+	results, late = run(WithBoundaryPolicy(PreferComplete), WithClock(afterDeadline))
+	as.Equal([]Result{{Value: 1}}, results)
+	as.Len(late.handled, 1)
+}
@@ -0,0 +1,69 @@
+package job
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// WithComputeBudget 限制一次执行中所有任务累计的执行耗时（任务秒），与墙上时间的等待时长无关，适合按调用时长计费的场景
+// 每个任务执行结束（包括返回错误、panic）时累加其耗时，超过 total 后尚未开始执行的任务不再执行，
+// 结果的 Error 为 ErrComputeBudgetExceeded，照常交付；此时正在执行的任务默认允许执行完，
+// 设置 WithComputeBudgetCancelRunning 后立即取消组上下文，正在执行的任务走超时处理
+// 重试的每次尝试都计入耗时；total <= 0 表示不限制（默认）
+func WithComputeBudget(total time.Duration) Option {
+	return computeBudgetOption(total)
+}
+
+type computeBudgetOption time.Duration
+
+func (c computeBudgetOption) bind(o *options) {
+	o.ComputeBudget = time.Duration(c)
+}
+
+// WithComputeBudgetCancelRunning 累计耗时超过 WithComputeBudget 时同时取消正在执行的任务
+func WithComputeBudgetCancelRunning() Option {
+	return computeBudgetCancelRunningOption(true)
+}
+
+type computeBudgetCancelRunningOption bool
+
+func (c computeBudgetCancelRunningOption) bind(o *options) {
+	o.ComputeBudgetCancelRunning = bool(c)
+}
+
+// computeBudget 一次执行的累计耗时
+type computeBudget struct {
+	total         time.Duration
+	cancelRunning bool
+	used          int64 // 累计耗时（纳秒），原子读写
+	spent         int32 // 已超过预算，原子读写
+}
+
+func (tg *Group) newComputeBudget() *computeBudget {
+	if tg.computeBudget <= 0 {
+		return nil
+	}
+	return &computeBudget{total: tg.computeBudget, cancelRunning: tg.computeBudgetCancelRunning}
+}
+
+// exceeded 是否已超过预算，b 为 nil 时返回 false
+func (b *computeBudget) exceeded() bool {
+	return b != nil && atomic.LoadInt32(&b.spent) == 1
+}
+
+// charge 累加一个任务的执行耗时，第一次超过预算时记录日志，按配置取消组上下文
+func (ex *execution) charge(d time.Duration) {
+	b := ex.budget
+	used := time.Duration(atomic.AddInt64(&b.used, int64(d)))
+	if used <= b.total || !atomic.CompareAndSwapInt32(&b.spent, 0, 1) {
+		return
+	}
+	ex.logInfo("compute budget exceeded", map[string]interface{}{
+		"budget":         b.total.String(),
+		"used":           used.String(),
+		"cancel_running": b.cancelRunning,
+	})
+	if b.cancelRunning {
+		ex.cancelWith(CanceledByComputeBudget)
+	}
+}
@@ -0,0 +1,68 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeBudget(t *testing.T) {
+	as := assert.New(t)
+
+	var executed int32
+	log := &memLog{}
+	tg := NewTaskGroup("compute_budget", WithDuration(5*time.Second), WithCollectRet(), WithLog(log),
+		WithMaxConcurrency(1), WithComputeBudget(50*time.Millisecond))
+	for i := 0; i < 5; i++ {
+		tg.AddTaskFunc(func() (interface{}, error) {
+			atomic.AddInt32(&executed, 1)
+			time.Sleep(20 * time.Millisecond)
+			return nil, nil
+		})
+	}
+
+	results, err := tg.Execute()
+	as.NoError(err)
+	as.Len(results, 5)
+	rejected := 0
+	for _, r := range results {
+		if errors.Is(r.Error, ErrComputeBudgetExceeded) {
+			rejected++
+		}
+	}
+	as.GreaterOrEqual(int(atomic.LoadInt32(&executed)), 2)
+	as.GreaterOrEqual(rejected, 1)
+	as.Equal(5, int(atomic.LoadInt32(&executed))+rejected)
+	as.Equal(1, log.infoCount("compute budget exceeded"))
+}
+
+func TestComputeBudgetCancelRunning(t *testing.T) {
+	as := assert.New(t)
+
+	cancelled := make(chan error, 1)
+	tg := NewTaskGroup("compute_budget_cancel", WithDuration(5*time.Second), WithCollectRet(), WithLog(&memLog{}),
+		WithComputeBudget(10*time.Millisecond), WithComputeBudgetCancelRunning())
+	tg.AddTaskFunc(func() (interface{}, error) {
+		time.Sleep(20 * time.Millisecond)
+		return 1, nil
+	})
+	tg.AddTask(TaskFuncCtx(func(ctx context.Context) (interface{}, error) {
+		select {
+		case <-ctx.Done():
+			cancelled <- ctx.Err()
+		case <-time.After(time.Second):
+			cancelled <- nil
+		}
+		return nil, ctx.Err()
+	}))
+
+	start := time.Now()
+	_, err := tg.Execute()
+	as.NoError(err)
+	as.Less(time.Since(start), 500*time.Millisecond)
+	as.ErrorIs(<-cancelled, context.Canceled)
+}
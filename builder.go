@@ -0,0 +1,139 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Builder 以链式调用配置并创建任务组，是 NewTaskGroup 加 Option 之外的另一种写法：
+//
+//	tg, err := job.NewBuilder("name").Timeout(time.Second).MaxConcurrency(4).Collect().AddFunc(fn).Build()
+//
+// 每个设置对应一个 Option，没有对应方法的选项通过 With 传入；互相冲突的设置在 Build 时返回错误
+// Builder 不是并发安全的，Build 之后可继续修改并再次 Build 出新的任务组
+type Builder struct {
+	name string
+	opts []Option
+	adds []func(tg *Group)
+}
+
+// NewBuilder 创建名为 name 的任务组构建器
+func NewBuilder(name string) *Builder {
+	return &Builder{name: name}
+}
+
+// With 追加任意选项，按调用顺序生效
+func (b *Builder) With(opts ...Option) *Builder {
+	b.opts = append(b.opts, opts...)
+	return b
+}
+
+// Timeout 设置等待时长，同 WithDuration
+func (b *Builder) Timeout(d time.Duration) *Builder {
+	return b.With(WithDuration(d))
+}
+
+// MaxConcurrency 限制同时执行的任务数，同 WithMaxConcurrency
+func (b *Builder) MaxConcurrency(n int) *Builder {
+	return b.With(WithMaxConcurrency(n))
+}
+
+// Collect 收集任务结果，同 WithCollectRet，需要同时设置 Timeout
+func (b *Builder) Collect() *Builder {
+	return b.With(WithCollectRet())
+}
+
+// Sequential 按添加顺序逐个执行任务，同 WithSequential
+func (b *Builder) Sequential() *Builder {
+	return b.With(WithSequential())
+}
+
+// Staged 分阶段执行，同 WithStagedExecution
+func (b *Builder) Staged() *Builder {
+	return b.With(WithStagedExecution())
+}
+
+// StageFailFast 某个阶段有任务失败后不再执行后面的阶段，同 WithStageFailFast，需要同时设置 Staged
+func (b *Builder) StageFailFast() *Builder {
+	return b.With(WithStageFailFast())
+}
+
+// RequireAnySuccess 没有任何任务成功时返回 ErrAllFailed，同 WithRequireAnySuccess
+func (b *Builder) RequireAnySuccess() *Builder {
+	return b.With(WithRequireAnySuccess())
+}
+
+// Retries 任务失败后的重试次数，同 WithRetries
+func (b *Builder) Retries(n int) *Builder {
+	return b.With(WithRetries(n))
+}
+
+// Log 设置日志，同 WithLog
+func (b *Builder) Log(log Logger) *Builder {
+	return b.With(WithLog(log))
+}
+
+// Context 设置组上下文，同 WithCtx
+func (b *Builder) Context(ctx context.Context) *Builder {
+	return b.With(WithCtx(ctx))
+}
+
+// Add 添加任务
+func (b *Builder) Add(t Tasker) *Builder {
+	b.adds = append(b.adds, func(tg *Group) { tg.AddTask(t) })
+	return b
+}
+
+// AddFunc 添加函数任务
+func (b *Builder) AddFunc(fn TaskFunc) *Builder {
+	b.adds = append(b.adds, func(tg *Group) { tg.AddTaskFunc(fn) })
+	return b
+}
+
+// AddNamed 添加命名任务，同 Group.AddNamedTask
+func (b *Builder) AddNamed(name string, t Tasker) *Builder {
+	b.adds = append(b.adds, func(tg *Group) { tg.AddNamedTask(name, t) })
+	return b
+}
+
+// Build 按累积的设置创建任务组并添加任务，设置冲突时返回包装了 ErrConflictingOptions（或 ErrNegativeDuration、
+// ErrNoTimeoutForCollect）的错误和 nil；任务依赖的检查同执行时
+func (b *Builder) Build() (*Group, error) {
+	tg := NewTaskGroup(b.name, b.opts...)
+	for _, add := range b.adds {
+		add(tg)
+	}
+	if err := tg.checkOptions(); err != nil {
+		return nil, err
+	}
+	if err := tg.checkDependencies(); err != nil {
+		return nil, err
+	}
+	return tg, nil
+}
+
+// checkOptions 检查互相冲突的设置
+func (tg *Group) checkOptions() error {
+	if tg.timeout < 0 {
+		return tg.durationError()
+	}
+	if tg.collectResult && !tg.isTimeout() {
+		return tg.configError(ErrNoTimeoutForCollect)
+	}
+
+	var conflict string
+	switch {
+	case tg.sequential && tg.maxConcurrency > 0:
+		conflict = "sequential execution with max concurrency"
+	case tg.sequential && tg.adaptiveConcurrency.max > 0:
+		conflict = "sequential execution with adaptive concurrency"
+	case tg.maxConcurrency > 0 && tg.adaptiveConcurrency.max > 0:
+		conflict = "max concurrency with adaptive concurrency"
+	case tg.stageFailFast && !tg.stagedExecution:
+		conflict = "stage fail-fast without staged execution"
+	default:
+		return nil
+	}
+	return tg.configError(fmt.Errorf("%w: %s", ErrConflictingOptions, conflict))
+}
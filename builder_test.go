@@ -0,0 +1,55 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	as := assert.New(t)
+
+	tg, err := NewBuilder("builder").Timeout(time.Second).MaxConcurrency(1).Collect().
+		Add(newTestSt("normal", 0, true)).
+		AddFunc(func() (interface{}, error) { return "func", nil }).
+		Build()
+	as.NoError(err)
+	as.Equal("builder", tg.name)
+	as.Equal(1, tg.maxConcurrency)
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.ElementsMatch([]Result{{Value: "normal"}, {Value: "func"}}, ret)
+
+	// 没有对应方法的选项通过 With 传入
+	tg, err = NewBuilder("builder").With(WithDuration(time.Second), WithCollectRet(), WithAllowEmpty()).Build()
+	as.NoError(err)
+	ret, err = tg.Execute()
+	as.NoError(err)
+	as.Empty(ret)
+}
+
+func TestBuilderConflicts(t *testing.T) {
+	as := assert.New(t)
+
+	for _, b := range []*Builder{
+		NewBuilder("conflict").Timeout(time.Second).Sequential().MaxConcurrency(2),
+		NewBuilder("conflict").With(WithAdaptiveConcurrency(1, 4)).MaxConcurrency(2),
+		NewBuilder("conflict").StageFailFast(),
+	} {
+		tg, err := b.Build()
+		as.ErrorIs(err, ErrConflictingOptions)
+		as.ErrorContains(err, `"conflict"`)
+		as.Nil(tg)
+	}
+
+	_, err := NewBuilder("negative").Timeout(-time.Second).Build()
+	as.ErrorIs(err, ErrNegativeDuration)
+	_, err = NewBuilder("collect").Collect().Build()
+	as.ErrorIs(err, ErrNoTimeoutForCollect)
+	_, err = NewBuilder("deps").AddNamed("a", newTestSt("a", 0, true)).
+		With(WithDuration(time.Second)).Build()
+	as.NoError(err)
+	_, err = NewBuilder("staged").Staged().StageFailFast().Build()
+	as.NoError(err)
+}
@@ -0,0 +1,99 @@
+package job
+
+import (
+	"context"
+	"time"
+)
+
+// WithBulkhead 限制类别为 category 的任务同时执行的数量最多为 max，用于隔离混合负载：
+// 例如给慢的报表任务单独设上限，使其不能占满 WithMaxConcurrency 的空位而饿死快的查询任务；
+// 任务用 Categorized 指定类别，没有类别的任务属于默认类别 ""，可用 WithBulkhead("", n) 限制；
+// 没有设置上限的类别只受组的并发限制；任务先等待类别的空位再等待组的空位，等待期间不占用组的空位，
+// 排队超时（WithQueueTimeout）同时计算两者的等待时间；可多次设置不同类别，max <= 0 表示不限制该类别
+func WithBulkhead(category string, max int) Option {
+	return bulkheadOption{category: category, max: max}
+}
+
+type bulkheadOption struct {
+	category string
+	max      int
+}
+
+func (b bulkheadOption) bind(o *options) {
+	if o.Bulkheads == nil {
+		o.Bulkheads = make(map[string]int)
+	}
+	o.Bulkheads[b.category] = b.max
+}
+
+// CategoryTask 为任务指定隔离舱类别，见 WithBulkhead
+type CategoryTask struct {
+	Tasker
+	Category string
+}
+
+// Categorized 把任务 t 归入类别 category
+func Categorized(t Tasker, category string) *CategoryTask {
+	return &CategoryTask{Tasker: t, Category: category}
+}
+
+// Unwrap 返回被包装的任务，组通过它识别被包装任务实现的可选接口
+func (c *CategoryTask) Unwrap() Tasker {
+	return c.Tasker
+}
+
+// taskCategory 返回任务的类别，没有类别时为 ""
+func taskCategory(t Tasker) string {
+	if c, ok := taskAs[*CategoryTask](t); ok {
+		return c.Category
+	}
+	return ""
+}
+
+// newBulkheads 为本次执行创建各类别的空位，ex.bulkheads 按任务下标索引，没有设置上限时为 nil
+func (tg *Group) newBulkheads(ex *execution) {
+	sems := make(map[string]chan struct{})
+	for category, max := range tg.bulkheads {
+		if max > 0 {
+			sems[category] = make(chan struct{}, max)
+		}
+	}
+	if len(sems) == 0 {
+		return
+	}
+	ex.bulkheads = make([]chan struct{}, len(tg.tasks))
+	for i, t := range tg.tasks {
+		ex.bulkheads[i] = sems[taskCategory(t)]
+	}
+}
+
+// bulkhead 返回任务 i 所属类别的空位，不限制时为 nil
+func (ex *execution) bulkhead(i int) chan struct{} {
+	if ex.bulkheads == nil {
+		return nil
+	}
+	return ex.bulkheads[i]
+}
+
+// acquireBulkhead 等待任务 i 所属类别的空位，expired 为排队超时的信号
+func (ex *execution) acquireBulkhead(ctx context.Context, i int, expired <-chan time.Time) error {
+	sem := ex.bulkhead(i)
+	if sem == nil {
+		return nil
+	}
+	select {
+	case sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-expired:
+		return ErrQueueTimeout
+	}
+}
+
+// releaseBulkhead 释放任务 i 所属类别的空位
+func (ex *execution) releaseBulkhead(i int) {
+	if sem := ex.bulkhead(i); sem != nil {
+		<-sem
+	}
+}
@@ -0,0 +1,79 @@
+package job
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// peakCounter 记录同时执行的最大任务数
+type peakCounter struct {
+	mu         sync.Mutex
+	cur, peak  int
+	finishedAt []time.Duration
+}
+
+func (c *peakCounter) task(start time.Time, d time.Duration) TaskFunc {
+	return func() (interface{}, error) {
+		c.mu.Lock()
+		c.cur++
+		c.peak = max(c.peak, c.cur)
+		c.mu.Unlock()
+		time.Sleep(d)
+		c.mu.Lock()
+		c.cur--
+		c.finishedAt = append(c.finishedAt, time.Since(start))
+		c.mu.Unlock()
+		return nil, nil
+	}
+}
+
+func TestBulkhead(t *testing.T) {
+	as := assert.New(t)
+
+	reports, lookups := &peakCounter{}, &peakCounter{}
+	tg := NewTaskGroup("bulkhead", WithDuration(2*time.Second), WithCollectRet(),
+		WithMaxConcurrency(3), WithBulkhead("report", 1), WithBulkhead("", 2))
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		tg.AddTask(Categorized(reports.task(start, 50*time.Millisecond), "report"))
+	}
+	for i := 0; i < 4; i++ {
+		tg.AddTaskFunc(lookups.task(start, 10*time.Millisecond))
+	}
+
+	results, err := tg.Execute()
+	as.NoError(err)
+	as.Len(results, 7)
+	as.Equal(1, reports.peak)
+	as.Equal(2, lookups.peak)
+	// 报表任务只占一个空位，查询任务不会排在全部报表任务之后
+	for _, d := range lookups.finishedAt {
+		as.Less(d, 100*time.Millisecond)
+	}
+}
+
+func TestBulkheadQueueTimeout(t *testing.T) {
+	as := assert.New(t)
+
+	reports := &peakCounter{}
+	tg := NewTaskGroup("bulkhead_queue_timeout", WithDuration(time.Second), WithCollectRet(),
+		WithBulkhead("report", 1), WithQueueTimeout(20*time.Millisecond))
+	start := time.Now()
+	tg.AddTask(Categorized(reports.task(start, 100*time.Millisecond), "report"))
+	tg.AddTask(Categorized(reports.task(start, 100*time.Millisecond), "report"))
+
+	results, err := tg.Execute()
+	as.NoError(err)
+	as.Len(results, 2)
+	timedOut := 0
+	for _, r := range results {
+		if r.Error == ErrQueueTimeout {
+			timedOut++
+		}
+	}
+	as.Equal(1, timedOut)
+	as.Equal(1, reports.peak)
+}
@@ -0,0 +1,87 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// CancelReason 任务组停止等待未结束任务的原因，见 GroupResult.CancelReason
+type CancelReason int
+
+const (
+	NotCanceled             CancelReason = iota // 所有任务在截止前结束，或仍在异步执行
+	CanceledByDeadline                          // 到达等待时长（WithDuration）
+	CanceledByParent                            // 上级上下文（WithCtx、WithContext、ExecuteDeadline 的 ctx）被取消或到期
+	CanceledByCaller                            // 调用了 ExecChanWithCancel 返回的取消函数
+	CanceledByUntil                             // ExecuteUntil 的条件满足
+	CanceledByStream                            // 流式输出结果（ExecuteJSON）失败
+	CanceledByComputeBudget                     // 超过 WithComputeBudget 且设置了 WithComputeBudgetCancelRunning
+	CanceledByStall                             // 超过 WithStallTimeout 没有任何任务完成
+)
+
+func (r CancelReason) String() string {
+	switch r {
+	case NotCanceled:
+		return "not canceled"
+	case CanceledByDeadline:
+		return "deadline"
+	case CanceledByParent:
+		return "parent context"
+	case CanceledByCaller:
+		return "caller"
+	case CanceledByUntil:
+		return "until condition"
+	case CanceledByStream:
+		return "stream error"
+	case CanceledByComputeBudget:
+		return "compute budget"
+	case CanceledByStall:
+		return "stalled"
+	default:
+		return "unknown"
+	}
+}
+
+// CancelError 任务组被主动取消（调用方、ExecuteUntil、流式输出失败、计算预算、停滞）时组上下文和任务上下文的 context.Cause，
+// 包装 context.Canceled，因停滞取消时 errors.Is(err, ErrStalled) 也为 true；到达等待时长或上级上下文结束时 Cause 保持原样（context.DeadlineExceeded 或上级的原因）
+type CancelError struct {
+	Reason CancelReason
+}
+
+func (e *CancelError) Error() string {
+	return "task group canceled: " + e.Reason.String()
+}
+
+func (e *CancelError) Unwrap() error {
+	return context.Canceled
+}
+
+func (e *CancelError) Is(target error) bool {
+	return target == ErrStalled && e.Reason == CanceledByStall
+}
+
+// cancelWith 以 reason 取消组上下文，只记录第一个原因
+func (ex *execution) cancelWith(reason CancelReason) {
+	if ex.ctx.Err() == nil {
+		atomic.CompareAndSwapInt32(&ex.reason, 0, int32(reason))
+	}
+	ex.cancelCause(&CancelError{Reason: reason})
+	ex.cancel()
+}
+
+// noteCancel 收集结束时组上下文已结束，记录没有经过 cancelWith 的原因（等待时长、上级上下文）；
+// 所有任务结束后的清理性取消（WithEagerCancel 等）不是停止等待的原因，不记录
+func (ex *execution) noteCancel() {
+	switch {
+	case ex.parent != nil && ex.parent.Err() != nil:
+		atomic.CompareAndSwapInt32(&ex.reason, 0, int32(CanceledByParent))
+	case errors.Is(context.Cause(ex.ctx), context.DeadlineExceeded):
+		atomic.CompareAndSwapInt32(&ex.reason, 0, int32(CanceledByDeadline))
+	}
+}
+
+// cancelReason 返回本次执行的取消原因
+func (ex *execution) cancelReason() CancelReason {
+	return CancelReason(atomic.LoadInt32(&ex.reason))
+}
@@ -0,0 +1,70 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingTask 阻塞到上下文结束，把 context.Cause 发到 cause
+func blockingTask(cause chan<- error) TaskFuncCtx {
+	return func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		cause <- context.Cause(ctx)
+		return nil, ctx.Err()
+	}
+}
+
+func TestCancelReason(t *testing.T) {
+	as := assert.New(t)
+
+	// 所有任务按时结束
+	tg := NewTaskGroup("cancel_none", WithDuration(time.Second), WithCollectRet())
+	tg.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+	as.Equal(NotCanceled, (<-tg.ExecChan()).CancelReason)
+
+	// 到达等待时长
+	cause := make(chan error, 1)
+	tg = NewTaskGroup("cancel_deadline", WithDuration(20*time.Millisecond), WithCollectRet())
+	tg.AddTaskFuncCtx(blockingTask(cause))
+	as.Equal(CanceledByDeadline, (<-tg.ExecChan()).CancelReason)
+	as.ErrorIs(<-cause, context.DeadlineExceeded)
+
+	// 上级上下文被取消
+	parent, cancelParent := context.WithCancel(context.Background())
+	tg = NewTaskGroup("cancel_parent", WithDuration(time.Second), WithCollectRet(), WithCtx(parent))
+	tg.AddTaskFuncCtx(blockingTask(cause))
+	time.AfterFunc(20*time.Millisecond, cancelParent)
+	as.Equal(CanceledByParent, (<-tg.ExecChan()).CancelReason)
+	as.ErrorIs(<-cause, context.Canceled)
+
+	// 调用方取消
+	tg = NewTaskGroup("cancel_caller", WithDuration(time.Second), WithCollectRet())
+	tg.AddTaskFuncCtx(blockingTask(cause))
+	ch, cancel := tg.ExecChanWithCancel()
+	time.AfterFunc(20*time.Millisecond, cancel)
+	as.Equal(CanceledByCaller, (<-ch).CancelReason)
+	err := <-cause
+	var cancelErr *CancelError
+	as.True(errors.As(err, &cancelErr))
+	as.Equal(CanceledByCaller, cancelErr.Reason)
+	as.ErrorIs(err, context.Canceled)
+	as.Equal("task group canceled: caller", err.Error())
+}
+
+func TestCancelReasonUntil(t *testing.T) {
+	as := assert.New(t)
+
+	cause := make(chan error, 1)
+	tg := NewTaskGroup("cancel_until", WithDuration(time.Second), WithLog(&memLog{}))
+	tg.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+	tg.AddTaskFuncCtx(blockingTask(cause))
+	_, err := tg.ExecuteUntil(func(results []Result) bool { return len(results) == 1 })
+	as.NoError(err)
+	var cancelErr *CancelError
+	as.True(errors.As(<-cause, &cancelErr))
+	as.Equal(CanceledByUntil, cancelErr.Reason)
+}
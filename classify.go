@@ -0,0 +1,77 @@
+package job
+
+// Outcome 任务最终状态在 WithRequireAnySuccess 判断中的归类
+type Outcome int
+
+const (
+	OutcomeFailure Outcome = iota // 计为失败，其错误包装进 ErrAllFailed
+	OutcomeSuccess                // 计为成功，满足“任一成功”
+	OutcomeIgnored                // 既不算成功也不算失败
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeFailure:
+		return "failure"
+	case OutcomeSuccess:
+		return "success"
+	case OutcomeIgnored:
+		return "ignored"
+	default:
+		return "unknown"
+	}
+}
+
+// WithClassify 自定义 WithRequireAnySuccess 对任务最终状态的归类：
+// 任一任务归为 OutcomeSuccess 时组成功；否则只要有任务归为 OutcomeFailure，GroupResult.Error 为 ErrAllFailed，
+// 只包装归为失败的任务的错误；全部归为 OutcomeIgnored 时不返回错误
+// 默认（不设置或 fn 为 nil）只有 StatusSucceeded 为成功，失败、跳过、panic、超时（包括被取消）都为失败；
+// 使用兜底值的任务按其 Status 归类。fn 在收集协程和任务协程中调用，需并发安全
+func WithClassify(fn func(TaskStatus) Outcome) Option {
+	return classifyOption(fn)
+}
+
+type classifyOption func(TaskStatus) Outcome
+
+func (c classifyOption) bind(o *options) {
+	o.Classify = c
+}
+
+// defaultClassify 默认归类，只有正常完成算成功
+func defaultClassify(s TaskStatus) Outcome {
+	if s == StatusSucceeded {
+		return OutcomeSuccess
+	}
+	return OutcomeFailure
+}
+
+func (tg *Group) classifier() func(TaskStatus) Outcome {
+	if tg.classify == nil {
+		return defaultClassify
+	}
+	return tg.classify
+}
+
+// allFailed 按归类判断是否没有任务成功且至少有一个任务失败
+func (ex *execution) allFailed(s Stats) bool {
+	counts := [...]int{
+		StatusSucceeded: s.Succeeded,
+		StatusFailed:    s.Failed,
+		StatusSkipped:   s.Skipped,
+		StatusPanicked:  s.Panicked,
+		StatusTimedOut:  s.TimedOut,
+	}
+	failed := false
+	for status, n := range counts {
+		if n == 0 {
+			continue
+		}
+		switch ex.classify(TaskStatus(status)) {
+		case OutcomeSuccess:
+			return false
+		case OutcomeFailure:
+			failed = true
+		}
+	}
+	return failed
+}
@@ -0,0 +1,76 @@
+package job
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	as := assert.New(t)
+
+	failed := errors.New("failed")
+	failing := func() (interface{}, error) { return nil, failed }
+	skipping := func() (interface{}, error) { return nil, Skip() }
+	panicking := func() (interface{}, error) { panic("boom") }
+	timingOut := newTestSt("timeout", 100*time.Millisecond, true)
+
+	run := func(classify func(TaskStatus) Outcome, tasks ...Tasker) error {
+		tg := NewTaskGroup("classify", WithCollectRet(), WithDuration(30*time.Millisecond),
+			WithRequireAnySuccess(), WithClassify(classify), WithLog(&memLog{}))
+		for _, task := range tasks {
+			tg.AddTask(task)
+		}
+		_, err := tg.Execute()
+		return err
+	}
+
+	// 默认只有成功算成功，其余状态都算失败
+	for _, task := range []Tasker{TaskFunc(failing), TaskFunc(skipping), TaskFunc(panicking), timingOut} {
+		as.ErrorIs(run(nil, task), ErrAllFailed)
+	}
+	as.NoError(run(nil, TaskFunc(failing), newTestSt("normal", 0, true)))
+
+	// panic 归为成功
+	panicOK := func(s TaskStatus) Outcome {
+		if s == StatusSucceeded || s == StatusPanicked {
+			return OutcomeSuccess
+		}
+		return OutcomeFailure
+	}
+	as.NoError(run(panicOK, TaskFunc(failing), TaskFunc(panicking)))
+
+	// 跳过和超时不计入
+	ignore := func(s TaskStatus) Outcome {
+		switch s {
+		case StatusSucceeded:
+			return OutcomeSuccess
+		case StatusSkipped, StatusTimedOut:
+			return OutcomeIgnored
+		default:
+			return OutcomeFailure
+		}
+	}
+	as.NoError(run(ignore, TaskFunc(skipping), timingOut))
+	err := run(ignore, TaskFunc(skipping), TaskFunc(failing), timingOut)
+	as.ErrorIs(err, ErrAllFailed)
+	as.ErrorIs(err, failed)
+	as.NotErrorIs(err, ErrSkipped)
+	as.NotContains(err.Error(), "timed out")
+
+	// panic 不计入时只剩失败
+	err = run(func(s TaskStatus) Outcome {
+		if s == StatusPanicked {
+			return OutcomeIgnored
+		}
+		return defaultClassify(s)
+	}, TaskFunc(panicking), TaskFunc(failing))
+	as.ErrorIs(err, ErrAllFailed)
+	as.NotContains(err.Error(), "boom")
+
+	as.Equal("ignored", OutcomeIgnored.String())
+
+	time.Sleep(100 * time.Millisecond)
+}
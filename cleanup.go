@@ -0,0 +1,39 @@
+package job
+
+// AddTaskWithCleanup 添加带清理函数的任务，无论任务正常结束、返回错误、panic、超时还是未开始就被取消，
+// 任务协程结束前都会调用一次 cleanup；cleanup 在超时处理（TimeoutHandler）和 panic 日志之后调用，
+// 其自身的 panic 会被恢复并记录日志
+// 超时时组不等待任务结束，cleanup 在任务真正结束后才调用，可能晚于执行结果返回
+func (tg *Group) AddTaskWithCleanup(t Tasker, cleanup func()) {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+
+	if tg.cleanups == nil {
+		tg.cleanups = make(map[int]func())
+	}
+	tg.cleanups[len(tg.tasks)] = cleanup
+	tg.tasks = append(tg.tasks, t)
+}
+
+// newCleanups 复制本次执行的清理函数，调用方需持有 tg.mu
+func (tg *Group) newCleanups(ex *execution) {
+	if len(tg.cleanups) == 0 {
+		return
+	}
+	ex.cleanups = make(map[int]func(), len(tg.cleanups))
+	for i, fn := range tg.cleanups {
+		ex.cleanups[i] = fn
+	}
+}
+
+// cleanup 调用任务 i 的清理函数，恢复其 panic
+func (ex *execution) cleanup(fn func(), i int) {
+	defer func() {
+		if r := recover(); r != nil {
+			ex.logError("task cleanup error", &PanicError{Value: r}, map[string]interface{}{
+				"i": i,
+			})
+		}
+	}()
+	fn()
+}
@@ -0,0 +1,46 @@
+package job
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddTaskWithCleanup(t *testing.T) {
+	as := assert.New(t)
+
+	var mu sync.Mutex
+	cleaned := make(map[string]bool)
+	cleanup := func(name string) func() {
+		return func() {
+			mu.Lock()
+			cleaned[name] = true
+			mu.Unlock()
+		}
+	}
+
+	log := &memLog{}
+	slow := newTimeoutSt("slow", 50*time.Millisecond)
+	tg := NewTaskGroup("cleanup", WithCollectRet(), WithDuration(20*time.Millisecond), WithLog(log))
+	tg.AddTaskWithCleanup(newTestSt("normal", 0, true), cleanup("normal"))
+	tg.AddTaskWithCleanup(TaskFunc(func() (interface{}, error) { return nil, errors.New("failed") }), cleanup("failed"))
+	tg.AddTaskWithCleanup(TaskFunc(func() (interface{}, error) { panic("boom") }), cleanup("panicked"))
+	tg.AddTaskWithCleanup(slow, func() {
+		as.Len(slow.timedOut, 1) // 超时处理先于清理
+		cleanup("slow")()
+	})
+	tg.AddTaskWithCleanup(newTestSt("normal2", 0, true), func() { panic("cleanup") })
+
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Len(ret, 3)
+
+	time.Sleep(60 * time.Millisecond)
+	mu.Lock()
+	as.Equal(map[string]bool{"normal": true, "failed": true, "panicked": true, "slow": true}, cleaned)
+	mu.Unlock()
+	as.Equal(1, log.errCount("task cleanup error"))
+}
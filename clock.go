@@ -0,0 +1,122 @@
+package job
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WithTimeoutFromStart 等待时长从第一个任务真正开始执行时计时，而不是从开始执行任务组时计时
+// WithDuration 的计时包含任务启动前的调度和等待执行空位（WithMaxConcurrency）的时间，
+// 设置本选项后这段等待不计入等待时长，适合只关心任务执行耗时的场景
+// 计时开始前组上下文没有截止时间；到时后组上下文被取消，context.Cause 为 context.DeadlineExceeded
+func WithTimeoutFromStart() Option {
+	return timeoutFromStartOption(true)
+}
+
+// WithMaxExtension 允许任务通过 Extend 延长组的等待时长，所有延长累计不超过 d
+// 设置后组上下文不再带截止时间，到时后被取消，context.Cause 为 context.DeadlineExceeded
+func WithMaxExtension(d time.Duration) Option {
+	return maxExtensionOption(d)
+}
+
+// Extend 在任务中调用，请求把组的截止时间推后 d，返回实际延长的时长
+// 累计延长受 WithMaxExtension 限制，未设置、已到时或组已结束时返回 0
+func Extend(ctx context.Context, d time.Duration) time.Duration {
+	dt, ok := ctx.Value(deadlineTimerKey{}).(*deadlineTimer)
+	if !ok {
+		return 0
+	}
+	return dt.extend(d)
+}
+
+type deadlineTimerKey struct{}
+
+// deadlineTimer 可推迟开始、可延长的组等待时长计时器
+type deadlineTimer struct {
+	once     sync.Once
+	mu       sync.Mutex
+	d        time.Duration
+	deadline time.Time
+	timer    *time.Timer
+	stopped  bool
+	cancel   context.CancelCauseFunc
+
+	maxExtension time.Duration
+	extended     time.Duration
+
+	paused    bool          // Pause 期间为 true
+	held      bool          // 计时器因暂停停止，恢复时以 remaining 重新计时
+	remaining time.Duration // 暂停时剩余的时长
+}
+
+// withDeadlineTimer 返回尚未开始计时的上下文，计时器的 stop 同时取消上下文
+func withDeadlineTimer(parent context.Context, d, maxExtension time.Duration) (context.Context, *deadlineTimer) {
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancelCause(parent)
+	dt := &deadlineTimer{d: d, cancel: cancel, maxExtension: maxExtension}
+	if maxExtension > 0 {
+		ctx = context.WithValue(ctx, deadlineTimerKey{}, dt)
+	}
+	return ctx, dt
+}
+
+// start 开始计时，只有第一次调用生效，dt 为 nil 时不做任何事
+func (dt *deadlineTimer) start() {
+	if dt == nil {
+		return
+	}
+	dt.once.Do(func() {
+		dt.mu.Lock()
+		defer dt.mu.Unlock()
+		if !dt.stopped {
+			dt.deadline = time.Now().Add(dt.d)
+			dt.timer = time.AfterFunc(dt.d, func() { dt.cancel(context.DeadlineExceeded) })
+			if dt.paused {
+				// 暂停期间开始计时，等恢复后再计时
+				dt.timer.Stop()
+				dt.remaining = dt.d
+				dt.held = true
+			}
+		}
+	})
+}
+
+// extend 延长等待时长，返回实际延长的时长
+func (dt *deadlineTimer) extend(d time.Duration) time.Duration {
+	dt.mu.Lock()
+	defer dt.mu.Unlock()
+
+	if d > dt.maxExtension-dt.extended {
+		d = dt.maxExtension - dt.extended
+	}
+	if d <= 0 || dt.stopped {
+		return 0
+	}
+	if dt.timer == nil {
+		// 尚未开始计时，直接加到等待时长上
+		dt.d += d
+	} else if dt.held {
+		// 暂停中，加到剩余时长上
+		dt.remaining += d
+	} else if dt.timer.Stop() {
+		dt.deadline = dt.deadline.Add(d)
+		dt.timer.Reset(time.Until(dt.deadline))
+	} else {
+		return 0 // 已到时
+	}
+	dt.extended += d
+	return d
+}
+
+func (dt *deadlineTimer) stop() {
+	dt.mu.Lock()
+	defer dt.mu.Unlock()
+	dt.stopped = true
+	if dt.timer != nil {
+		dt.timer.Stop()
+	}
+	dt.cancel(context.Canceled)
+}
@@ -0,0 +1,55 @@
+package job
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeoutFromStart(t *testing.T) {
+	as := assert.New(t)
+
+	ctx, clock := withDeadlineTimer(context.Background(), 20*time.Millisecond, 0)
+	time.Sleep(40 * time.Millisecond)
+	as.NoError(ctx.Err()) // 未开始计时
+	clock.start()
+	clock.start()
+	<-ctx.Done()
+	as.ErrorIs(context.Cause(ctx), context.DeadlineExceeded)
+	clock.stop()
+
+	slow := newTimeoutSt("slow", 100*time.Millisecond)
+	tg := NewTaskGroup("timeout_from_start", WithCollectRet(), WithDuration(30*time.Millisecond), WithTimeoutFromStart())
+	tg.AddTask(newTestSt("normal", 0, true))
+	tg.AddTask(slow)
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Equal([]Result{{Value: "normal"}}, ret)
+	as.Equal("slow", <-slow.timedOut)
+}
+
+func TestMaxExtension(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("extension", WithCollectRet(), WithDuration(30*time.Millisecond), WithMaxExtension(50*time.Millisecond))
+	tg.AddTaskFuncCtx(func(ctx context.Context) (interface{}, error) {
+		granted := Extend(ctx, 40*time.Millisecond)
+		granted += Extend(ctx, 40*time.Millisecond) // 累计不超过上限
+		time.Sleep(60 * time.Millisecond)
+		return granted, ctx.Err()
+	})
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Equal([]Result{{Value: 50 * time.Millisecond}}, ret)
+
+	// 未设置上限时不能延长
+	tg = NewTaskGroup("no_extension", WithCollectRet(), WithDuration(time.Second))
+	tg.AddTaskFuncCtx(func(ctx context.Context) (interface{}, error) {
+		return Extend(ctx, time.Second), nil
+	})
+	ret, err = tg.Execute()
+	as.NoError(err)
+	as.Equal([]Result{{Value: time.Duration(0)}}, ret)
+}
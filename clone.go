@@ -0,0 +1,34 @@
+package job
+
+// Cloner 可复制的任务，设置 WithCloneTasks 时每次执行使用 Clone 返回的副本
+type Cloner interface {
+	Clone() Tasker
+}
+
+// WithCloneTasks 每次执行前对实现了 Cloner 的任务调用 Clone，执行副本而不是添加的实例，
+// 使持有可变状态的任务可以在多次（包括并发的）执行间安全复用；Clone 应返回状态独立的新实例
+// 只检查添加的任务本身：被 Labeled 等包装的任务需要包装本身实现 Cloner；未实现 Cloner 的任务直接执行原实例，
+// 多次执行共享其状态，需要自行保证并发安全
+func WithCloneTasks() Option {
+	return cloneTasksOption(true)
+}
+
+type cloneTasksOption bool
+
+func (c cloneTasksOption) bind(o *options) {
+	o.CloneTasks = bool(c)
+}
+
+// runTasks 返回本次执行的任务列表，设置 WithCloneTasks 时为副本，调用方需持有 tg.mu
+func (tg *Group) runTasks() []Tasker {
+	tasks := append([]Tasker(nil), tg.tasks...)
+	if !tg.cloneTasks {
+		return tasks
+	}
+	for i, t := range tasks {
+		if c, ok := t.(Cloner); ok {
+			tasks[i] = c.Clone()
+		}
+	}
+	return tasks
+}
@@ -0,0 +1,55 @@
+package job
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// statefulSt 每次执行修改自身状态，不是并发安全的
+type statefulSt struct {
+	calls int
+}
+
+func (s *statefulSt) Execute() (interface{}, error) {
+	s.calls++
+	return s.calls, nil
+}
+
+func (s *statefulSt) Clone() Tasker {
+	return &statefulSt{}
+}
+
+func TestCloneTasks(t *testing.T) {
+	as := assert.New(t)
+
+	shared := &statefulSt{}
+	run := func() []Result {
+		tg := NewTaskGroup("clone", WithDuration(time.Second), WithCollectRet(), WithCloneTasks())
+		tg.AddTask(shared)
+		results, err := tg.Execute()
+		as.NoError(err)
+		return results
+	}
+
+	// 并发执行各自使用副本，互不影响，原实例的状态不变
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			as.Equal([]Result{{Value: 1}}, run())
+		}()
+	}
+	wg.Wait()
+	as.Equal(0, shared.calls)
+
+	// 不设置 WithCloneTasks 时执行原实例
+	tg := NewTaskGroup("no_clone", WithDuration(time.Second), WithCollectRet())
+	tg.AddTask(shared)
+	_, err := tg.Execute()
+	as.NoError(err)
+	as.Equal(1, shared.calls)
+}
@@ -0,0 +1,154 @@
+package job
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"time"
+)
+
+// WithMaxConcurrency 限制同时执行的任务数，n <= 0 表示不限制（默认）
+// 所有任务仍同时启动协程，超出限制的任务等待空位；等待期间超时或被取消的任务不再执行，直接走超时处理
+// 依赖任务在依赖结束后才占用空位，不会因等待依赖而占满并发
+func WithMaxConcurrency(n int) Option {
+	return maxConcurrencyOption(n)
+}
+
+// WithConcurrencyByCPU 按 ceil(GOMAXPROCS * multiplier) 限制同时执行的任务数，至少为 1，
+// 适合 CPU 密集型任务，GOMAXPROCS 在创建任务组时读取
+func WithConcurrencyByCPU(multiplier float64) Option {
+	n := int(math.Ceil(float64(runtime.GOMAXPROCS(0)) * multiplier))
+	if n < 1 {
+		n = 1
+	}
+	return maxConcurrencyOption(n)
+}
+
+// WithQueueTimeout 设置任务等待执行空位（WithMaxConcurrency）的最长时间，默认只受等待时长限制
+// 排队超时的任务不再执行，结果的 Error 为 ErrQueueTimeout、Status 为 StatusTimedOut，照常交付；
+// 任务实现 TaskQueueTimeout 时调用 QueueTimeoutHandler，否则走超时处理（err 为 ErrQueueTimeout），
+// 据此可以区分系统饱和（排队超时）和任务执行慢（执行超时）
+func WithQueueTimeout(d time.Duration) Option {
+	return queueTimeoutOption(d)
+}
+
+// TaskQueueTimeout 排队超时的处理
+type TaskQueueTimeout interface {
+	QueueTimeoutHandler(err error)
+}
+
+// handleQueueTimeout 调用任务的排队超时处理，没有实现 TaskQueueTimeout 时走超时处理
+func (tg *Group) handleQueueTimeout(ctx context.Context, t Tasker, ret Result) {
+	if out, ok := taskAs[TaskQueueTimeout](t); ok {
+		out.QueueTimeoutHandler(ret.Error)
+		return
+	}
+	tg.handleTimeout(ctx, t, ret)
+}
+
+func newSemaphore(n int) chan struct{} {
+	if n <= 0 {
+		return nil
+	}
+	return make(chan struct{}, n)
+}
+
+// acquire 等待任务 i 所属类别（WithBulkhead）和组的执行空位，成功返回 nil，
+// ctx 先结束返回 ctx.Err()，超过 WithQueueTimeout 返回 ErrQueueTimeout
+func (ex *execution) acquire(ctx context.Context, i int) error {
+	if ex.sem == nil && ex.bulkhead(i) == nil {
+		return nil
+	}
+	var expired <-chan time.Time
+	if ex.queueTimeout > 0 {
+		timer := time.NewTimer(ex.queueTimeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	if err := ex.acquireBulkhead(ctx, i, expired); err != nil {
+		return err
+	}
+	if ex.sem == nil {
+		return nil
+	}
+	select {
+	case ex.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		ex.releaseBulkhead(i)
+		return ctx.Err()
+	case <-expired:
+		ex.releaseBulkhead(i)
+		return ErrQueueTimeout
+	}
+}
+
+// acquireTimed 等待任务 i 的执行空位，累计等待时长
+func (ex *execution) acquireTimed(ctx context.Context, i int) error {
+	if ex.queueWaits == nil {
+		return ex.acquireInTurn(ctx, i)
+	}
+	start := time.Now()
+	defer func() { ex.queueWaits[i] += time.Since(start) }()
+	return ex.acquireInTurn(ctx, i)
+}
+
+// queueWait 任务 i 等待执行空位的累计时长，没有并发限制（WithMaxConcurrency、WithBulkhead）时为 0，只在任务自己的协程中调用
+func (ex *execution) queueWait(i int) time.Duration {
+	if ex.queueWaits == nil {
+		return 0
+	}
+	return ex.queueWaits[i]
+}
+
+// waitStart 等待开始信号、暂停恢复和任务 i 的执行空位，返回不能开始执行的原因
+// 累计耗时已超过 WithComputeBudget 时返回 ErrComputeBudgetExceeded
+func (ex *execution) waitStart(ctx context.Context, i int) error {
+	if !ex.waitGate(ctx) {
+		return ctx.Err()
+	}
+	for {
+		if err := ex.waitResume(ctx); err != nil {
+			return err
+		}
+		if err := ex.acquireTimed(ctx, i); err != nil {
+			return err
+		}
+		if ex.budget.exceeded() {
+			ex.release(i)
+			return ErrComputeBudgetExceeded
+		}
+		if ex.pause.wait() == nil {
+			return nil
+		}
+		ex.release(i) // 等到空位时组已暂停，让出空位继续等待恢复
+	}
+}
+
+// release 释放任务 i 的执行空位
+func (ex *execution) release(i int) {
+	if ex.sem != nil {
+		<-ex.sem
+	}
+	ex.releaseBulkhead(i)
+}
+
+// WithStartGate 每个任务开始执行前等待 gate 关闭（或收到值），用于同时开始（压测）或等待就绪信号
+// 等待期间超时或被取消的任务不再执行，直接走超时处理；等待时长照常从开始执行任务组时计时
+// 关闭 gate 会同时唤醒所有任务，任务同时访问下游可能造成瞬时压力（惊群），必要时配合 WithMaxConcurrency
+func WithStartGate(gate <-chan struct{}) Option {
+	return startGateOption(gate)
+}
+
+// waitGate 等待开始信号，ctx 结束前没有等到返回 false
+func (ex *execution) waitGate(ctx context.Context) bool {
+	if ex.gate == nil {
+		return true
+	}
+	select {
+	case <-ex.gate:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,119 @@
+package job
+
+import (
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxConcurrency(t *testing.T) {
+	as := assert.New(t)
+
+	var running, peak int32
+	tg := NewTaskGroup("max_concurrency", WithCollectRet(), WithDuration(time.Second), WithMaxConcurrency(2))
+	for i := 0; i < 6; i++ {
+		tg.AddTaskFunc(func() (interface{}, error) {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return i, nil
+		})
+	}
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Len(ret, 6)
+	as.Equal(int32(2), peak)
+
+	// 等待空位时超时的任务不再执行，走超时处理
+	slow := newTimeoutSt("slow", 100*time.Millisecond)
+	slow2 := newTimeoutSt("slow2", 100*time.Millisecond)
+	tg = NewTaskGroup("max_concurrency_timeout", WithCollectRet(), WithDuration(30*time.Millisecond), WithMaxConcurrency(1))
+	tg.AddTask(slow)
+	tg.AddTask(slow2)
+	ret, err = tg.Execute()
+	as.NoError(err)
+	as.Empty(ret)
+	handled := []interface{}{<-slow.timedOut, <-slow2.timedOut}
+	as.Contains(handled, nil)
+	as.True(handled[0] != nil || handled[1] != nil)
+}
+
+func TestConcurrencyByCPU(t *testing.T) {
+	as := assert.New(t)
+
+	procs := runtime.GOMAXPROCS(0)
+	as.Equal(2*procs, NewTaskGroup("cpu", WithConcurrencyByCPU(2)).maxConcurrency)
+	as.Equal(1, NewTaskGroup("cpu", WithConcurrencyByCPU(0)).maxConcurrency)
+	as.Equal((procs+1)/2, NewTaskGroup("cpu", WithConcurrencyByCPU(0.5)).maxConcurrency)
+}
+
+func TestStartGate(t *testing.T) {
+	as := assert.New(t)
+
+	gate := make(chan struct{})
+	var started int32
+	tg := NewTaskGroup("start_gate", WithCollectRet(), WithDuration(time.Second), WithStartGate(gate))
+	for i := 0; i < 3; i++ {
+		tg.AddTaskFunc(func() (interface{}, error) {
+			atomic.AddInt32(&started, 1)
+			return i, nil
+		})
+	}
+	ch := tg.ExecChan()
+	time.Sleep(20 * time.Millisecond)
+	as.Equal(int32(0), atomic.LoadInt32(&started))
+	close(gate)
+	grs := <-ch
+	as.NoError(grs.Error)
+	as.Len(grs.Results, 3)
+
+	// 信号未到达前超时的任务不执行
+	task := newTimeoutSt("gated", 0)
+	tg = NewTaskGroup("start_gate_timeout", WithDuration(20*time.Millisecond), WithStartGate(make(chan struct{})))
+	tg.AddTask(task)
+	_, err := tg.Execute()
+	as.NoError(err)
+	as.Nil(<-task.timedOut)
+}
+
+// queueSt 记录排队超时
+type queueSt struct {
+	*test_st
+	queued chan error
+}
+
+func (s *queueSt) QueueTimeoutHandler(err error) {
+	s.queued <- err
+}
+
+func TestQueueTimeout(t *testing.T) {
+	as := assert.New(t)
+
+	queued := make(chan error, 2)
+	tg := NewTaskGroup("queue_timeout", WithCollectRet(), WithDuration(time.Second),
+		WithMaxConcurrency(1), WithQueueTimeout(20*time.Millisecond))
+	tg.AddTask(&queueSt{test_st: newTestSt("slow", 50*time.Millisecond, true), queued: queued})
+	tg.AddTask(&queueSt{test_st: newTestSt("slow2", 50*time.Millisecond, true), queued: queued})
+	grs := <-tg.ExecChan()
+	as.NoError(grs.Error)
+	as.Len(grs.Results, 2)
+
+	var errs []error
+	for _, r := range grs.Results {
+		errs = append(errs, r.Error)
+	}
+	as.Contains(errs, ErrQueueTimeout) // 排队超时的结果照常交付
+	as.Equal(1, grs.Stats.Succeeded)
+	as.Equal(1, grs.Stats.TimedOut)
+	as.Equal(ErrQueueTimeout, <-queued)
+	as.Empty(queued)
+}
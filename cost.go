@@ -0,0 +1,94 @@
+package job
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// EstimatedTask 可以预估执行耗时的任务，配合 WithCostOrdering 使用
+type EstimatedTask interface {
+	EstimatedCost() time.Duration
+}
+
+// WithCostOrdering 按预估耗时从高到低启动任务（最长处理时间优先），在 WithMaxConcurrency 等并发限制下
+// 按该顺序依次获得执行空位，用于缩短耗时差异大的一组任务的总耗时；这是启发式的策略，效果取决于预估的准确性
+// 没有实现 EstimatedTask 的任务按最低耗时处理，耗时相同时保持添加顺序；
+// 依赖任务在依赖结束后才排队，不参与排序；顺序执行（WithSequential）时不生效
+func WithCostOrdering() Option {
+	return costOrderingOption(true)
+}
+
+type costOrderingOption bool
+
+func (c costOrderingOption) bind(o *options) {
+	o.CostOrdering = bool(c)
+}
+
+// turn 任务按顺序获取执行空位的位置，wait 关闭后轮到该任务，获取或放弃后关闭 done
+type turn struct {
+	wait <-chan struct{}
+	done chan struct{}
+}
+
+// launchOrder 返回启动任务的下标顺序，设置 WithCostOrdering 时按预估耗时降序；
+// 按阶段执行时先按阶段排序，避免后面阶段的任务排在前面阶段的任务之前获取空位
+func (tg *Group) launchOrder(ex *execution) []int {
+	order := make([]int, len(tg.tasks))
+	for i := range order {
+		order[i] = i
+	}
+	if !tg.costOrdering {
+		return order
+	}
+	staged := ex.stages != nil && ex.stages.ordered
+	sort.SliceStable(order, func(a, b int) bool {
+		if staged && ex.stages.ord[order[a]] != ex.stages.ord[order[b]] {
+			return ex.stages.ord[order[a]] < ex.stages.ord[order[b]]
+		}
+		return estimatedCost(tg.tasks[order[a]]) > estimatedCost(tg.tasks[order[b]])
+	})
+	return order
+}
+
+func estimatedCost(t Tasker) time.Duration {
+	if et, ok := taskAs[EstimatedTask](t); ok {
+		return et.EstimatedCost()
+	}
+	return 0
+}
+
+// newTurns 有并发限制时按启动顺序为非依赖任务排好获取空位的顺序
+func (tg *Group) newTurns(ex *execution, order []int) {
+	if !tg.costOrdering || ex.sem == nil {
+		return
+	}
+	ex.turns = make([]turn, len(tg.tasks))
+	var prev <-chan struct{}
+	for _, i := range order {
+		if _, ok := tg.tasks[i].(*dependentTask); ok {
+			continue
+		}
+		done := make(chan struct{})
+		ex.turns[i] = turn{wait: prev, done: done}
+		prev = done
+	}
+}
+
+// acquireInTurn 轮到任务 i 后再等待执行空位，没有排序的任务直接等待
+func (ex *execution) acquireInTurn(ctx context.Context, i int) error {
+	if ex.turns == nil || ex.turns[i].done == nil {
+		return ex.acquire(ctx, i)
+	}
+	t := ex.turns[i]
+	ex.turns[i] = turn{} // 只排一次队，暂停后重新等待空位时不再排队
+	defer close(t.done)
+	if t.wait != nil {
+		select {
+		case <-t.wait:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return ex.acquire(ctx, i)
+}
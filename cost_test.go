@@ -0,0 +1,65 @@
+package job
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// costSt 预估耗时为 cost 的任务，执行时记录名称
+type costSt struct {
+	name  string
+	cost  time.Duration
+	mu    *sync.Mutex
+	order *[]string
+}
+
+func (s *costSt) Execute() (interface{}, error) {
+	s.mu.Lock()
+	*s.order = append(*s.order, s.name)
+	s.mu.Unlock()
+	time.Sleep(time.Millisecond)
+	return s.name, nil
+}
+
+func (s *costSt) EstimatedCost() time.Duration {
+	return s.cost
+}
+
+func TestCostOrdering(t *testing.T) {
+	as := assert.New(t)
+
+	var mu sync.Mutex
+	var order []string
+	tg := NewTaskGroup("cost", WithDuration(time.Second), WithMaxConcurrency(1), WithCostOrdering())
+	tg.AddTaskFunc(func() (interface{}, error) { // 没有预估按最低耗时
+		mu.Lock()
+		order = append(order, "none")
+		mu.Unlock()
+		return nil, nil
+	})
+	tg.AddTask(&costSt{name: "small", cost: time.Millisecond, mu: &mu, order: &order})
+	tg.AddTask(&costSt{name: "large", cost: time.Second, mu: &mu, order: &order})
+	tg.AddTask(Labeled(&costSt{name: "medium", cost: 10 * time.Millisecond, mu: &mu, order: &order}, nil))
+	tg.AddTask(&costSt{name: "large2", cost: time.Second, mu: &mu, order: &order})
+
+	_, err := tg.Execute()
+	as.NoError(err)
+	as.Equal([]string{"large", "large2", "medium", "small", "none"}, order)
+}
+
+func TestLaunchOrder(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("launch_order")
+	tg.AddTask(&costSt{cost: time.Millisecond})
+	tg.AddTask(&costSt{cost: time.Second})
+	as.Equal([]int{0, 1}, tg.launchOrder(&execution{})) // 未设置时按添加顺序
+
+	tg = NewTaskGroup("launch_order_cost", WithCostOrdering())
+	tg.AddTask(&costSt{cost: time.Millisecond})
+	tg.AddTask(&costSt{cost: time.Second})
+	as.Equal([]int{1, 0}, tg.launchOrder(&execution{}))
+}
@@ -0,0 +1,169 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DependentTask 依赖其他命名任务的任务工厂，所有依赖结束后以 依赖名 -> 结果 调用，返回实际执行的任务
+type DependentTask func(deps map[string]Result) Tasker
+
+// DependencyPolicy 依赖失败（返回错误、panic 或超时）时的处理策略
+type DependencyPolicy int
+
+const (
+	// SkipOnDependencyFailure 不调用工厂，任务记为跳过，错误满足 errors.Is(err, ErrDependencyFailed)
+	SkipOnDependencyFailure DependencyPolicy = iota
+	// PropagateDependencyFailure 照常调用工厂，由工厂自行处理失败的依赖结果
+	PropagateDependencyFailure
+)
+
+type dependencyPolicyOption DependencyPolicy
+
+func (d dependencyPolicyOption) bind(o *options) {
+	o.DependencyPolicy = DependencyPolicy(d)
+}
+
+// WithDependencyPolicy 设置依赖失败时的处理策略，默认 SkipOnDependencyFailure
+func WithDependencyPolicy(p DependencyPolicy) Option {
+	return dependencyPolicyOption(p)
+}
+
+// dependentTask 组内保存的依赖任务，执行时由 runTask 解析
+type dependentTask struct {
+	deps    []string
+	factory DependentTask
+}
+
+func (d *dependentTask) Execute() (interface{}, error) {
+	return nil, errors.New("dependent task must be executed by its group")
+}
+
+// depSlot 命名任务在一次执行中的结果，done 关闭后 ret 可读
+type depSlot struct {
+	name string
+	done chan struct{}
+	ret  Result
+}
+
+// AddNamedTask 添加命名任务，可被依赖任务引用
+func (tg *Group) AddNamedTask(name string, t Tasker) {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+
+	if tg.names == nil {
+		tg.names = make(map[int]string)
+	}
+	tg.names[len(tg.tasks)] = name
+	tg.tasks = append(tg.tasks, t)
+}
+
+// AddDependentTask 添加名为 name 的依赖任务，deps 中的任务全部结束后才调用 factory 并执行其返回的任务
+// 依赖任务自身也可以被其他依赖任务引用
+func (tg *Group) AddDependentTask(name string, deps []string, factory DependentTask) {
+	tg.AddNamedTask(name, &dependentTask{deps: deps, factory: factory})
+}
+
+// checkDependencies 检查任务名重复、依赖不存在以及循环依赖，调用方需持有 tg.mu
+func (tg *Group) checkDependencies() error {
+	index := make(map[string]int, len(tg.names))
+	for i, name := range tg.names {
+		if _, ok := index[name]; ok {
+			return fmt.Errorf("duplicate task name %q", name)
+		}
+		index[name] = i
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[int]int, len(index))
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visiting:
+			return fmt.Errorf("dependency cycle at task %q", tg.names[i])
+		case visited:
+			return nil
+		}
+		state[i] = visiting
+		if dt, ok := tg.tasks[i].(*dependentTask); ok {
+			for _, dep := range dt.deps {
+				j, ok := index[dep]
+				if !ok {
+					return fmt.Errorf("task %q depends on unknown task %q", tg.names[i], dep)
+				}
+				if err := visit(j); err != nil {
+					return err
+				}
+			}
+		}
+		state[i] = visited
+		return nil
+	}
+	for i := range tg.names {
+		if err := visit(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newSlots 为命名任务创建本次执行的结果槽，调用方需持有 tg.mu
+func (tg *Group) newSlots(ex *execution) {
+	if len(tg.names) == 0 {
+		return
+	}
+	ex.slots = make(map[string]*depSlot, len(tg.names))
+	ex.slotOf = make([]*depSlot, len(tg.tasks))
+	for i, name := range tg.names {
+		slot := &depSlot{name: name, done: make(chan struct{})}
+		ex.slots[name] = slot
+		ex.slotOf[i] = slot
+	}
+}
+
+// publish 写入命名任务的最终结果，唤醒等待它的依赖任务
+func (s *depSlot) publish(ret Result) {
+	s.ret = ret
+	close(s.done)
+}
+
+// slotResult 命名任务发布给依赖任务的结果：超时（截止、被 CancelTask 取消）的任务即使返回了 nil 错误
+// 也记为 StatusTimedOut，错误为任务上下文的取消原因，依赖任务据此按依赖失败处理
+func slotResult(ctx context.Context, ret Result, timedOut bool) Result {
+	if timedOut {
+		ret.Status = StatusTimedOut
+		if ret.Error == nil {
+			ret.Error = context.Cause(ctx)
+		}
+	}
+	return ret
+}
+
+// resolveDependent 等待依赖结束并按策略得到实际执行的任务
+// 返回 nil 任务时 ret 即为该任务的结果
+func (tg *Group) resolveDependent(ex *execution, ctx context.Context, dt *dependentTask) (Tasker, Result) {
+	deps := make(map[string]Result, len(dt.deps))
+	failed := false
+	for _, name := range dt.deps {
+		slot := ex.slots[name]
+		select {
+		case <-slot.done:
+		case <-ctx.Done():
+			return nil, Result{Error: ctx.Err()}
+		}
+		deps[name] = slot.ret
+		if slot.ret.Error != nil || slot.ret.Status == StatusTimedOut {
+			failed = true
+		}
+	}
+
+	if failed && tg.dependencyPolicy == SkipOnDependencyFailure {
+		return nil, Result{Error: fmt.Errorf("%w: %w", ErrSkipped, ErrDependencyFailed)}
+	}
+	return dt.factory(deps), Result{}
+}
@@ -0,0 +1,140 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependentTask(t *testing.T) {
+	as := assert.New(t)
+
+	failed := errors.New("failed")
+	tg := NewTaskGroup("dag", WithCollectRet(), WithDuration(time.Second))
+	tg.AddNamedTask("user", TaskFunc(func() (interface{}, error) {
+		time.Sleep(20 * time.Millisecond)
+		return "tom", nil
+	}))
+	tg.AddNamedTask("broken", TaskFunc(func() (interface{}, error) {
+		return nil, failed
+	}))
+	tg.AddDependentTask("greet", []string{"user"}, func(deps map[string]Result) Tasker {
+		return TaskFunc(func() (interface{}, error) {
+			return "hello " + deps["user"].Value.(string), nil
+		})
+	})
+	tg.AddDependentTask("skipped", []string{"broken"}, func(deps map[string]Result) Tasker {
+		panic("should not be called")
+	})
+
+	ret, err := tg.Execute()
+	as.NoError(err)
+	values := make([]string, 0)
+	errs := make([]error, 0)
+	for _, r := range ret {
+		if r.Error != nil {
+			errs = append(errs, r.Error)
+			continue
+		}
+		values = append(values, r.Value.(string))
+	}
+	sort.Strings(values)
+	as.Equal([]string{"hello tom", "tom"}, values)
+	as.Len(errs, 2)
+	as.Contains(errs, failed)
+	for _, r := range ret {
+		if errors.Is(r.Error, ErrDependencyFailed) {
+			as.Equal(StatusSkipped, r.Status)
+		}
+	}
+}
+
+func TestDependentTaskPropagate(t *testing.T) {
+	as := assert.New(t)
+
+	failed := errors.New("failed")
+	tg := NewTaskGroup("dag_propagate", WithCollectRet(), WithDuration(time.Second),
+		WithDependencyPolicy(PropagateDependencyFailure))
+	tg.AddNamedTask("panic", TaskFunc(func() (interface{}, error) {
+		panic("boom")
+	}))
+	tg.AddNamedTask("broken", TaskFunc(func() (interface{}, error) {
+		return nil, failed
+	}))
+	tg.AddDependentTask("fallback", []string{"panic", "broken"}, func(deps map[string]Result) Tasker {
+		return TaskFunc(func() (interface{}, error) {
+			return deps["panic"].Error != nil && deps["broken"].Error == failed, nil
+		})
+	})
+
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Contains(ret, Result{Value: true})
+}
+
+// TestDependencyTimedOut 依赖超时但返回了 nil 错误时同样按依赖失败处理
+func TestDependencyTimedOut(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("dag_timeout", WithCollectRet(), WithDuration(time.Second))
+	tg.AddNamedTask("slow", &deadlineSt{
+		test_st:  newTestSt("slow", 50*time.Millisecond, true),
+		deadline: time.Now().Add(10 * time.Millisecond),
+		timedOut: make(chan interface{}, 1),
+	})
+	tg.AddDependentTask("after", []string{"slow"}, func(deps map[string]Result) Tasker {
+		panic("should not be called")
+	})
+
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Len(ret, 1)
+	as.ErrorIs(ret[0].Error, ErrDependencyFailed)
+	as.Equal(StatusSkipped, ret[0].Status)
+
+	// 传播策略下工厂拿到超时状态和取消原因
+	var dep Result
+	tg = NewTaskGroup("dag_timeout_propagate", WithCollectRet(), WithDuration(time.Second),
+		WithDependencyPolicy(PropagateDependencyFailure))
+	tg.AddNamedTask("slow", &deadlineSt{
+		test_st:  newTestSt("slow", 50*time.Millisecond, true),
+		deadline: time.Now().Add(10 * time.Millisecond),
+		timedOut: make(chan interface{}, 1),
+	})
+	tg.AddDependentTask("after", []string{"slow"}, func(deps map[string]Result) Tasker {
+		dep = deps["slow"]
+		return TaskFunc(func() (interface{}, error) { return "after", nil })
+	})
+	ret, err = tg.Execute()
+	as.NoError(err)
+	as.Equal([]Result{{Value: "after"}}, ret)
+	as.Equal(StatusTimedOut, dep.Status)
+	as.ErrorIs(dep.Error, context.DeadlineExceeded)
+}
+
+func TestDependencyCheck(t *testing.T) {
+	as := assert.New(t)
+
+	factory := func(deps map[string]Result) Tasker { return nil }
+
+	tg := NewTaskGroup("dag_unknown", WithDuration(time.Second))
+	tg.AddDependentTask("a", []string{"missing"}, factory)
+	_, err := tg.Execute()
+	as.ErrorContains(err, "unknown task")
+
+	tg = NewTaskGroup("dag_cycle", WithDuration(time.Second))
+	tg.AddDependentTask("a", []string{"b"}, factory)
+	tg.AddDependentTask("b", []string{"a"}, factory)
+	_, err = tg.Execute()
+	as.ErrorContains(err, "cycle")
+
+	tg = NewTaskGroup("dag_duplicate", WithDuration(time.Second))
+	tg.AddNamedTask("a", newTestSt("a", 0, true))
+	tg.AddNamedTask("a", newTestSt("a", 0, true))
+	_, err = tg.Execute()
+	as.ErrorContains(err, "duplicate")
+}
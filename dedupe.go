@@ -0,0 +1,75 @@
+package job
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// WithDedupeTasks 执行前按指针去重：同一个任务实例（指针）被添加多次时只保留第一次，
+// 其余从任务组中移除并输出 Error 日志 "duplicate task skipped"；值相同但不是同一实例的任务、
+// 非指针类型的任务（如 TaskFunc）以及以 AddNamedTask 命名添加的重复实例不去重（名称可能被依赖任务引用）
+func WithDedupeTasks() Option {
+	return dedupeTasksOption(true)
+}
+
+// dedupe 移除重复添加的任务实例，按下标记录的属性随之重新编号，调用方需持有 tg.mu
+func (tg *Group) dedupe() {
+	if !tg.dedupeTasks {
+		return
+	}
+	seen := make(map[interface{}]int, len(tg.tasks))
+	kept := make([]int, 0, len(tg.tasks)) // 新下标 -> 原下标
+	for i, t := range tg.tasks {
+		if t == nil || reflect.ValueOf(t).Kind() != reflect.Pointer {
+			kept = append(kept, i)
+			continue
+		}
+		if first, ok := seen[t]; ok {
+			if _, named := tg.names[i]; !named {
+				logError(tg.log, "duplicate task skipped", fmt.Errorf("task %d is the same instance as task %d", i, first),
+					map[string]interface{}{
+						"name":  tg.name,
+						"i":     i,
+						"first": first,
+					})
+				continue
+			}
+		} else {
+			seen[t] = i
+		}
+		kept = append(kept, i)
+	}
+	if len(kept) == len(tg.tasks) {
+		return
+	}
+
+	old := tg.swapTasks(taskSet{})
+	for _, i := range kept {
+		n := len(tg.tasks)
+		tg.tasks = append(tg.tasks, old.tasks[i])
+		if name, ok := old.names[i]; ok {
+			tg.names = setIndex(tg.names, n, name)
+		}
+		if fallback, ok := old.fallbacks[i]; ok {
+			tg.fallbacks = setIndex(tg.fallbacks, n, fallback)
+		}
+		if ctx, ok := old.taskCtxs[i]; ok {
+			tg.taskCtxs = setIndex(tg.taskCtxs, n, ctx)
+		}
+		if cleanup, ok := old.cleanups[i]; ok {
+			tg.cleanups = setIndex(tg.cleanups, n, cleanup)
+		}
+		if stage, ok := old.stages[i]; ok {
+			tg.stages = setIndex(tg.stages, n, stage)
+		}
+	}
+}
+
+// setIndex 写入按下标记录的属性，m 为 nil 时先创建
+func setIndex[V any](m map[int]V, i int, v V) map[int]V {
+	if m == nil {
+		m = make(map[int]V)
+	}
+	m[i] = v
+	return m
+}
@@ -0,0 +1,68 @@
+package job
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countSt struct {
+	runs int32
+}
+
+func (s *countSt) Execute() (interface{}, error) {
+	return atomic.AddInt32(&s.runs, 1), nil
+}
+
+func TestDedupeTasks(t *testing.T) {
+	as := assert.New(t)
+
+	log := &memLog{}
+	a, b := &countSt{}, &countSt{}
+	tg := NewTaskGroup("dedupe", WithCollectRet(), WithDuration(time.Second), WithLog(log), WithDedupeTasks())
+	tg.AddTasks([]Tasker{a, a, b})
+	tg.AddTaskWithDefault(a, "fallback")
+	tg.AddTaskWithDefault(&countSt{}, "kept") // 值相同但不是同一实例，不去重
+
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Len(ret, 3)
+	as.Equal(int32(1), a.runs)
+	as.Equal(int32(1), b.runs)
+	as.Equal(2, log.errCount("duplicate task skipped"))
+	as.Equal(map[int]interface{}{2: "kept"}, tg.fallbacks) // 属性随下标重新编号
+
+	// 默认不去重
+	c := &countSt{}
+	tg = NewTaskGroup("no_dedupe", WithCollectRet(), WithDuration(time.Second))
+	tg.AddTasks([]Tasker{c, c})
+	ret, err = tg.Execute()
+	as.NoError(err)
+	as.Len(ret, 2)
+	as.Equal(int32(2), c.runs)
+}
+
+// TestDedupeNamedTasks 命名添加的重复实例不去重，依赖它的任务照常执行
+func TestDedupeNamedTasks(t *testing.T) {
+	as := assert.New(t)
+
+	log := &memLog{}
+	a := &countSt{}
+	tg := NewTaskGroup("dedupe_named", WithCollectRet(), WithDuration(time.Second), WithLog(log), WithDedupeTasks())
+	tg.AddNamedTask("a", a)
+	tg.AddNamedTask("b", a)
+	tg.AddTask(a) // 未命名的重复实例照常去重
+	tg.AddDependentTask("after", []string{"b"}, func(deps map[string]Result) Tasker {
+		return TaskFunc(func() (interface{}, error) { return "after", nil })
+	})
+
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Len(ret, 3)
+	as.Contains(ret, Result{Value: "after"})
+	as.Equal(int32(2), atomic.LoadInt32(&a.runs))
+	as.Equal(1, log.errCount("duplicate task skipped"))
+	as.Equal(map[int]string{0: "a", 1: "b", 2: "after"}, tg.names)
+}
@@ -0,0 +1,31 @@
+package job
+
+import (
+	"bytes"
+	"runtime/debug"
+)
+
+// GoDetached 在独立协程中执行 fn，完全脱离任务组的生命周期：不计入任务数、统计和结果，不受等待时长和取消影响，
+// 也不会被 Execute 等待，可能在任务组执行结束后仍在运行；fn 的 panic 会被恢复并用组的日志记录
+// 适合在任务中派生尽力而为的旁路工作（如刷新缓存、上报），需要结果或超时控制时应添加为任务
+func (tg *Group) GoDetached(fn func()) {
+	tg.mu.Lock()
+	log, name := tg.log, tg.name
+	tg.mu.Unlock()
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				stack := debug.Stack()
+				if line := bytes.IndexByte(stack, '\n'); line >= 0 {
+					stack = stack[line+1:]
+				}
+				logError(log, "detached task error", &PanicError{Value: r, Stack: stack}, map[string]interface{}{
+					"name":  name,
+					"stack": string(stack),
+				})
+			}
+		}()
+		fn()
+	}()
+}
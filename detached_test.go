@@ -0,0 +1,42 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoDetached(t *testing.T) {
+	as := assert.New(t)
+
+	log := &memLog{}
+	ran := make(chan struct{})
+	release := make(chan struct{})
+	tg := NewTaskGroup("detached", WithDuration(time.Second), WithCollectRet(), WithLog(log))
+	tg.AddTaskFunc(func() (interface{}, error) {
+		tg.GoDetached(func() {
+			<-release
+			close(ran)
+		})
+		return 1, nil
+	})
+
+	grs := <-tg.ExecChan()
+	as.NoError(grs.Error)
+	as.Equal(1, grs.Stats.Total) // 旁路工作不计入统计
+	as.Equal(1, grs.Stats.Succeeded)
+	as.Len(grs.Results, 1)
+
+	// 执行结束后仍在运行
+	close(release)
+	<-ran
+
+	done := make(chan struct{})
+	tg.GoDetached(func() {
+		defer close(done)
+		panic("boom")
+	})
+	<-done
+	as.Eventually(func() bool { return log.errCount("detached task error") == 1 }, time.Second, time.Millisecond)
+}
@@ -0,0 +1,56 @@
+package job
+
+import "errors"
+
+// ErrGroup 提交返回错误的函数，*errgroup.Group（golang.org/x/sync/errgroup）满足该接口
+type ErrGroup interface {
+	Go(f func() error)
+}
+
+// RunInErrGroup 把每个任务作为 eg 的一个函数提交，任务的错误（panic 为 *PanicError）即该函数返回的错误，
+// 由 eg.Wait 汇总，返回后不等待任务结束；跳过的任务（ErrSkipped）不算错误
+// 结果由 errgroup 持有，不收集结果、不调用结果回调，等待时长、超时处理、并发限制等选项照常生效；
+// 需要统一取消时以 errgroup.WithContext 返回的 ctx 作为 WithCtx，任一任务出错即取消其余任务
+// 顺序执行（WithSequential）时所有任务作为一个函数提交，返回合并后的错误
+func (tg *Group) RunInErrGroup(eg ErrGroup) error {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+
+	if len(tg.tasks) == 0 {
+		tg.runEarlyDefers()
+		if tg.allowEmpty {
+			return nil
+		}
+		return tg.configError(ErrNoTasks)
+	}
+	if err := tg.checkRun(); err != nil {
+		tg.runEarlyDefers()
+		return err
+	}
+
+	ex := tg.newExecution(tg.ctx)
+	ex.retChan = make(chan taskResult, ex.total) // 没有收集方，结果通道不能阻塞任务
+	ex.eagerCancel = true                        // 所有任务结束后释放上下文
+	ex.errGroup = eg
+	ex.errs = make([]error, ex.total)
+	tg.run(ex)
+	return nil
+}
+
+// taskErr 任务 i 在 errgroup 中返回的错误
+func (ex *execution) taskErr(i int) error {
+	return ex.errs[i]
+}
+
+// recordErr 记录任务 i 最终结果的错误，跳过不算错误
+func (ex *execution) recordErr(i int, ret Result) {
+	if ret.Status == StatusSkipped {
+		return
+	}
+	ex.errs[i] = ret.cause()
+}
+
+// sequentialErr 顺序执行时合并所有任务的错误
+func (ex *execution) sequentialErr() error {
+	return errors.Join(ex.errs...)
+}
@@ -0,0 +1,77 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeErrGroup 与 errgroup.Group 行为一致的最小实现：记录第一个错误并取消上下文
+type fakeErrGroup struct {
+	wg     sync.WaitGroup
+	once   sync.Once
+	err    error
+	cancel context.CancelFunc
+}
+
+func (g *fakeErrGroup) Go(f func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := f(); err != nil {
+			g.once.Do(func() {
+				g.err = err
+				if g.cancel != nil {
+					g.cancel()
+				}
+			})
+		}
+	}()
+}
+
+func (g *fakeErrGroup) Wait() error {
+	g.wg.Wait()
+	return g.err
+}
+
+func TestRunInErrGroup(t *testing.T) {
+	as := assert.New(t)
+
+	failed := errors.New("failed")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	eg := &fakeErrGroup{cancel: cancel}
+
+	tg := NewTaskGroup("errgroup", WithCtx(ctx), WithDuration(time.Second), WithLog(&memLog{}))
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, Skip() })
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, failed })
+	tg.AddTask(TaskFuncCtx(func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done() // 其他任务出错后被 errgroup 的上下文取消
+		return nil, ctx.Err()
+	}))
+
+	as.NoError(tg.RunInErrGroup(eg))
+	as.ErrorIs(eg.Wait(), failed)
+
+	// panic 转为 *PanicError
+	eg = &fakeErrGroup{}
+	tg = NewTaskGroup("errgroup_panic", WithLog(&memLog{}))
+	tg.AddTaskFunc(func() (interface{}, error) { panic("boom") })
+	as.NoError(tg.RunInErrGroup(eg))
+	var pe *PanicError
+	as.ErrorAs(eg.Wait(), &pe)
+
+	// 顺序执行合并错误
+	eg = &fakeErrGroup{}
+	tg = NewTaskGroup("errgroup_seq", WithSequential())
+	tg.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, failed })
+	as.NoError(tg.RunInErrGroup(eg))
+	as.ErrorIs(eg.Wait(), failed)
+
+	as.ErrorIs(NewTaskGroup("errgroup_empty").RunInErrGroup(eg), ErrNoTasks)
+}
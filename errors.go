@@ -0,0 +1,54 @@
+package job
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoTasks 组内没有任务，返回的错误包装了它并带上组名
+	ErrNoTasks = errors.New("no tasks to execute")
+	// ErrNoTimeoutForCollect 收集结果但未设置等待时长，返回的错误包装了它并带上组名
+	ErrNoTimeoutForCollect = errors.New("no timeout set for result collection")
+	// ErrNegativeDuration WithDuration 设置了负数，返回的错误包装了它并带上组名
+	ErrNegativeDuration = errors.New("negative duration")
+	// ErrNoDeadline ExecuteDeadline 的上下文没有截止时间且未设置等待时长，返回的错误包装了它并带上组名
+	ErrNoDeadline = errors.New("no deadline set for ExecuteDeadline")
+	// ErrConflictingOptions Builder.Build 时设置互相冲突，返回的错误包装了它并带上组名和冲突的设置
+	ErrConflictingOptions = errors.New("conflicting options")
+	// ErrSkipped 任务主动放弃执行时返回，结果记为跳过而不是失败
+	ErrSkipped = errors.New("task skipped")
+	// ErrDependencyFailed 依赖的任务失败或超时，SkipOnDependencyFailure 策略下被跳过任务的错误同时包装它和 ErrSkipped
+	ErrDependencyFailed = errors.New("dependency failed")
+	// ErrAllFailed 设置 WithRequireAnySuccess 时没有任何任务成功
+	ErrAllFailed = errors.New("all tasks failed")
+	// ErrQueueTimeout 任务等待执行空位超过 WithQueueTimeout，没有执行
+	ErrQueueTimeout = errors.New("task queue timeout")
+	// ErrPrepareFailed ExecutePhased 中有任务准备失败，所有任务已回滚
+	ErrPrepareFailed = errors.New("prepare failed")
+	// ErrStageFailed 设置 WithStageFailFast 时前面的阶段有任务失败，被跳过任务的错误同时包装它和 ErrSkipped
+	ErrStageFailed = errors.New("previous stage failed")
+	// ErrComputeBudgetExceeded 任务累计耗时超过 WithComputeBudget，任务没有执行
+	ErrComputeBudgetExceeded = errors.New("compute budget exceeded")
+	// ErrResultTooLarge 结果的值超过 WithMaxResultBytes，值已丢弃
+	ErrResultTooLarge = errors.New("result too large")
+	// ErrStalled 超过 WithStallTimeout 没有任何任务完成，任务组被取消，GroupResult.Error 包装它
+	ErrStalled = errors.New("stalled")
+)
+
+// PanicError 任务 panic 时结果的错误，保留 recover() 的原始值和调用栈
+// panic 的值本身是 error 时可通过 errors.Is / errors.As 取到它
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%v", e.Value)
+}
+
+// Unwrap panic 的值是 error 时返回它，否则返回 nil
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
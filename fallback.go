@@ -0,0 +1,86 @@
+package job
+
+import "context"
+
+// AddTaskWithDefault 添加带兜底值的任务，任务失败、panic 或超时时以 fallback 作为结果的 Value，
+// Error 为 nil，原始错误保存在 OriginalError，Status 仍反映任务本身的结果
+// 超时任务的兜底结果在收集结束时补齐，因此需要有等待时长
+func (tg *Group) AddTaskWithDefault(t Tasker, fallback interface{}) {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+
+	if tg.fallbacks == nil {
+		tg.fallbacks = make(map[int]interface{})
+	}
+	tg.fallbacks[len(tg.tasks)] = fallback
+	tg.tasks = append(tg.tasks, t)
+}
+
+// WithDefaultOnTimeout 任务超时（或被取消）没有交付结果、且没有通过 AddTaskWithDefault 设置兜底值时，
+// 以 fn(index) 作为该任务结果的 Value，原始超时错误保存在 OriginalError，Status 为 StatusTimedOut
+// 任务自身的兜底值优先；任务返回错误或 panic 时不使用 fn
+func WithDefaultOnTimeout(fn func(index int) interface{}) Option {
+	return defaultOnTimeoutOption(fn)
+}
+
+// newFallbacks 复制本次执行的兜底值，调用方需持有 tg.mu
+func (tg *Group) newFallbacks(ex *execution) {
+	ex.onTimeout = tg.defaultOnTimeout
+	if len(tg.fallbacks) == 0 && ex.onTimeout == nil {
+		return
+	}
+	ex.fallbacks = make(map[int]interface{}, len(tg.fallbacks))
+	for i, v := range tg.fallbacks {
+		ex.fallbacks[i] = v
+	}
+	ex.delivered = make([]bool, ex.total)
+}
+
+// output 返回任务 i 实际交付的结果，失败时替换为兜底值
+func (ex *execution) output(i int, ret Result) taskResult {
+	ret = ex.limitSize(ret)
+	if fallback, ok := ex.fallbacks[i]; ok && ret.Error != nil {
+		ret = Result{Value: fallback, OriginalError: ret.Error, Status: ret.Status}
+	}
+	ret.Stage = ex.stages.of(i)
+	return taskResult{index: i, Result: ret}
+}
+
+// fillFallbacks 为收集结束时仍未交付结果的任务补上兜底结果，任务自身的兜底值优先于 WithDefaultOnTimeout
+// 截止前已 panic 的任务不算超时，只按任务自身的兜底值补上 panic 的结果
+func (ex *execution) fillFallbacks(results []Result) []Result {
+	for i := 0; i < len(ex.delivered); i++ {
+		if ex.delivered[i] {
+			continue
+		}
+		if ret, ok := ex.panicResult(i); ok {
+			if _, ok := ex.fallbacks[i]; ok {
+				results = ex.accept(results, ex.output(i, ret))
+			}
+			continue
+		}
+		fallback, ok := ex.fallbacks[i]
+		if !ok && ex.onTimeout == nil {
+			continue
+		} else if !ok {
+			fallback = ex.onTimeout(i)
+		}
+		err := context.Cause(ex.ctxs[i])
+		if err == nil {
+			err = context.DeadlineExceeded
+		}
+		results = ex.accept(results, taskResult{
+			index:  i,
+			Result: Result{Value: fallback, OriginalError: err, Status: StatusTimedOut, Stage: ex.stages.of(i)},
+		})
+		ex.timeouts = append(ex.timeouts, TimeoutResult{Index: i, Value: fallback, Error: err})
+	}
+	return results
+}
+
+// TimeoutResult 超时任务在收集结束时补上的兜底值，见 GroupResult.TimeoutResults
+type TimeoutResult struct {
+	Index int         // 任务下标，与添加顺序一致
+	Value interface{} // AddTaskWithDefault 的兜底值或 WithDefaultOnTimeout 返回的值
+	Error error       // 任务超时的原因（context.Cause）
+}
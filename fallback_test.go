@@ -0,0 +1,120 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddTaskWithDefault(t *testing.T) {
+	as := assert.New(t)
+
+	failed := errors.New("failed")
+	tg := NewTaskGroup("fallback", WithCollectRet(), WithDuration(50*time.Millisecond), WithLog(&memLog{}))
+	tg.AddTaskWithDefault(newTestSt("normal", 0, true), "unused")
+	tg.AddTaskWithDefault(TaskFunc(func() (interface{}, error) { return nil, failed }), "failed")
+	tg.AddTaskWithDefault(TaskFunc(func() (interface{}, error) { panic("boom") }), "panicked")
+	tg.AddTaskWithDefault(newTestSt("timeout", 100*time.Millisecond, true), "timeout")
+
+	grs := <-tg.ExecChan()
+	as.NoError(grs.Error)
+	as.Len(grs.Results, 4)
+
+	byValue := make(map[interface{}]Result)
+	for _, r := range grs.Results {
+		as.NoError(r.Error)
+		byValue[r.Value] = r
+	}
+	as.Nil(byValue["normal"].OriginalError)
+	as.Equal(Result{Value: "failed", OriginalError: failed, Status: StatusFailed}, byValue["failed"])
+	as.EqualError(byValue["panicked"].OriginalError, "boom")
+	as.Equal(StatusPanicked, byValue["panicked"].Status)
+	as.ErrorIs(byValue["timeout"].OriginalError, context.DeadlineExceeded)
+	as.Equal(StatusTimedOut, byValue["timeout"].Status)
+
+	as.Equal(1, grs.Stats.Succeeded)
+	as.Equal(1, grs.Stats.Failed)
+	as.Equal(1, grs.Stats.Panicked)
+	as.Equal(1, grs.Stats.TimedOut)
+
+	time.Sleep(100 * time.Millisecond)
+}
+
+func TestDefaultOnTimeout(t *testing.T) {
+	as := assert.New(t)
+
+	failed := errors.New("failed")
+	tg := NewTaskGroup("default_on_timeout", WithCollectRet(), WithDuration(30*time.Millisecond),
+		WithDefaultOnTimeout(func(i int) interface{} { return i }))
+	tg.AddTask(newTestSt("normal", 0, true))
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, failed })
+	tg.AddTask(newTestSt("timeout", 100*time.Millisecond, true))
+	tg.AddTaskWithDefault(newTestSt("timeout2", 100*time.Millisecond, true), "own")
+
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.ElementsMatch([]Result{
+		{Value: "normal"},
+		{Error: failed, Status: StatusFailed}, // 失败不使用默认值
+		{Value: 2, OriginalError: context.DeadlineExceeded, Status: StatusTimedOut},
+		{Value: "own", OriginalError: context.DeadlineExceeded, Status: StatusTimedOut}, // 任务自身的兜底值优先
+	}, ret)
+
+	time.Sleep(100 * time.Millisecond)
+}
+
+func TestDefaultOnTimeoutPanic(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("default_on_timeout_panic", WithCollectRet(), WithDuration(30*time.Millisecond),
+		WithDefaultOnTimeout(func(i int) interface{} { return i }), WithLog(&memLog{}))
+	tg.AddTaskFunc(func() (interface{}, error) { panic("boom") })
+	tg.AddTask(newTestSt("timeout", 100*time.Millisecond, true))
+
+	grs := <-tg.ExecChan()
+	as.NoError(grs.Error)
+	as.Equal([]Result{{Value: 1, OriginalError: context.DeadlineExceeded, Status: StatusTimedOut}}, grs.Results) // panic 不使用默认值
+	as.Equal(1, grs.Stats.Panicked)
+	as.Equal(1, grs.Stats.TimedOut)
+
+	time.Sleep(100 * time.Millisecond)
+}
+
+func TestTimeoutResults(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("timeout_results", WithCollectRet(), WithDuration(30*time.Millisecond),
+		WithDefaultOnTimeout(func(i int) interface{} { return i * 10 }))
+	tg.AddTask(newTestSt("normal", 0, true))
+	tg.AddTaskWithDefault(newTestSt("timeout", 100*time.Millisecond, true), "own")
+	tg.AddTask(newTestSt("normal2", 0, true))
+	tg.AddTask(newTestSt("timeout2", 100*time.Millisecond, true))
+
+	grs := <-tg.ExecChan()
+	as.NoError(grs.Error)
+	as.Equal([]TimeoutResult{
+		{Index: 1, Value: "own", Error: context.DeadlineExceeded},
+		{Index: 3, Value: 30, Error: context.DeadlineExceeded},
+	}, grs.TimeoutResults)
+
+	time.Sleep(100 * time.Millisecond)
+}
+
+func TestTimeoutResultsPanic(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("timeout_results_panic", WithCollectRet(), WithDuration(30*time.Millisecond),
+		WithDefaultOnTimeout(func(i int) interface{} { return i * 10 }), WithLog(&memLog{}))
+	tg.AddTaskFunc(func() (interface{}, error) { panic("boom") })
+	tg.AddTask(newTestSt("timeout", 100*time.Millisecond, true))
+	tg.AddTaskWithDefault(TaskFunc(func() (interface{}, error) { panic("boom") }), "own")
+
+	grs := <-tg.ExecChan()
+	as.NoError(grs.Error)
+	as.Equal([]TimeoutResult{{Index: 1, Value: 10, Error: context.DeadlineExceeded}}, grs.TimeoutResults) // panic 的任务不算超时
+
+	time.Sleep(100 * time.Millisecond)
+}
@@ -0,0 +1,85 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// WithPerTaskTimeoutFraction 每个任务开始执行时以组剩余时长的 f 倍作为该任务的时限，避免一个慢任务耗尽整组的时间；
+// 时限在任务真正开始执行时（拿到执行空位之后）计算，越晚开始的任务时限越短，组截止时间始终是上限；
+// 任务到达时限后与组超时一样走超时处理，上下文的 Err 和 context.Cause 为 context.DeadlineExceeded，
+// Deadline 返回该时限；组没有截止时间时不生效；f 须在 (0, 1] 内，否则不生效（默认）
+func WithPerTaskTimeoutFraction(f float64) Option {
+	return perTaskTimeoutFractionOption(f)
+}
+
+type perTaskTimeoutFractionOption float64
+
+func (f perTaskTimeoutFractionOption) bind(o *options) {
+	o.PerTaskTimeoutFraction = float64(f)
+}
+
+// fractionContext 开始执行时才确定截止时间的任务上下文，截止时间取它与上级截止时间中较早者
+type fractionContext struct {
+	context.Context
+	cancel context.CancelCauseFunc
+
+	mu       sync.Mutex
+	deadline time.Time // 任务开始执行前为零值
+}
+
+// withFractionDeadline 为任务上下文 ctx 包装一层可在开始执行时设置截止时间的上下文，返回的取消函数同时取消两者
+func withFractionDeadline(ctx context.Context, cancel context.CancelFunc) (*fractionContext, context.CancelFunc) {
+	inner, cancelCause := context.WithCancelCause(ctx)
+	c := &fractionContext{Context: inner, cancel: cancelCause}
+	return c, func() {
+		cancelCause(context.Canceled)
+		cancel()
+	}
+}
+
+func (c *fractionContext) Deadline() (time.Time, bool) {
+	c.mu.Lock()
+	deadline := c.deadline
+	c.mu.Unlock()
+	if parent, ok := c.Context.Deadline(); ok && (deadline.IsZero() || parent.Before(deadline)) {
+		return parent, true
+	}
+	return deadline, !deadline.IsZero()
+}
+
+func (c *fractionContext) Err() error {
+	err := c.Context.Err()
+	if err != nil && errors.Is(context.Cause(c.Context), context.DeadlineExceeded) {
+		return context.DeadlineExceeded
+	}
+	return err
+}
+
+// startTaskTimeout 任务开始执行时按组剩余时长设置任务时限，返回停止计时的函数
+func (ex *execution) startTaskTimeout(ctx context.Context) func() {
+	c, ok := ctx.(*fractionContext)
+	if !ok {
+		return func() {}
+	}
+	deadline, ok := ex.deadline(ex.ctx)
+	if !ok {
+		return func() {}
+	}
+	d := time.Duration(float64(time.Until(deadline)) * ex.taskFraction)
+	c.mu.Lock()
+	c.deadline = time.Now().Add(d)
+	c.mu.Unlock()
+	timer := time.AfterFunc(d, func() { c.cancel(context.DeadlineExceeded) })
+	return func() { timer.Stop() }
+}
+
+// taskTimeoutFraction 返回生效的 WithPerTaskTimeoutFraction，不在 (0, 1] 内时返回 0
+func (tg *Group) taskTimeoutFraction() float64 {
+	if tg.perTaskTimeoutFraction <= 0 || tg.perTaskTimeoutFraction > 1 {
+		return 0
+	}
+	return tg.perTaskTimeoutFraction
+}
@@ -0,0 +1,76 @@
+package job
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fractionSt struct {
+	sleep   time.Duration
+	budget  chan time.Duration // 开始执行时任务的剩余时限
+	handled chan error
+}
+
+func (s *fractionSt) Execute() (interface{}, error) {
+	return nil, nil
+}
+
+func (s *fractionSt) ExecuteCtx(ctx context.Context) (interface{}, error) {
+	deadline, _ := ctx.Deadline()
+	s.budget <- time.Until(deadline)
+	select {
+	case <-time.After(s.sleep):
+		return "done", nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *fractionSt) TimeoutHandler(ret interface{}, err error) {
+	s.handled <- err
+}
+
+func TestPerTaskTimeoutFraction(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("task_timeout_fraction", WithDuration(400*time.Millisecond), WithCollectRet(),
+		WithSequential(), WithPerTaskTimeoutFraction(0.5))
+	first := &fractionSt{sleep: 100 * time.Millisecond, budget: make(chan time.Duration, 1), handled: make(chan error, 1)}
+	second := &fractionSt{sleep: time.Second, budget: make(chan time.Duration, 1), handled: make(chan error, 1)}
+	tg.AddTask(first)
+	tg.AddTask(second)
+
+	start := time.Now()
+	results, err := tg.Execute()
+	as.NoError(err)
+	as.Len(results, 1)
+	as.Equal("done", results[0].Value)
+
+	// 剩余 400ms 时开始得到约 200ms，剩余约 300ms 时开始得到约 150ms
+	as.InDelta(200*time.Millisecond, <-first.budget, float64(30*time.Millisecond))
+	as.InDelta(150*time.Millisecond, <-second.budget, float64(30*time.Millisecond))
+	as.ErrorIs(<-second.handled, context.DeadlineExceeded)
+	as.Less(time.Since(start), 350*time.Millisecond)
+}
+
+func TestPerTaskTimeoutFractionCeiling(t *testing.T) {
+	as := assert.New(t)
+
+	// f = 1 时任务时限等于组剩余时长，组截止时间是上限
+	tg := NewTaskGroup("task_timeout_fraction_ceiling", WithDuration(100*time.Millisecond), WithCollectRet(),
+		WithPerTaskTimeoutFraction(1))
+	task := &fractionSt{sleep: time.Second, budget: make(chan time.Duration, 1), handled: make(chan error, 1)}
+	tg.AddTask(task)
+
+	results, err := tg.Execute()
+	as.NoError(err)
+	as.Empty(results)
+	as.LessOrEqual(<-task.budget, 100*time.Millisecond)
+	as.ErrorIs(<-task.handled, context.DeadlineExceeded)
+
+	// 不在 (0, 1] 内不生效，任务只受组截止时间约束
+	as.Equal(0.0, NewTaskGroup("invalid", WithPerTaskTimeoutFraction(1.5)).taskTimeoutFraction())
+}
@@ -0,0 +1,96 @@
+package job
+
+import "sync"
+
+// AddTasksFromChan 从 ch 逐个读取工作项，用 f 创建任务添加到任务组，直到 ch 关闭，返回添加的任务数；f 返回 nil 时跳过该工作项
+// 添加的任务与其他任务一起在之后调用 Execute 等方法时作为一次执行开始，共享并发限制、重试和计算预算、结果收集与组级错误，
+// Running、CancelTask 等按添加顺序的下标针对这些任务。调用会阻塞到 ch 关闭：ch 永不关闭时不会返回，也不会执行任何任务，
+// 需要有限批次时由生产方在一批结束后关闭通道；工作项到达即开始执行见 ExecuteFromChan
+func AddTasksFromChan[T any](tg *Group, ch <-chan T, f func(T) Tasker) int {
+	n := 0
+	for item := range ch {
+		if t := f(item); t != nil {
+			tg.AddTask(t)
+			n++
+		}
+	}
+	return n
+}
+
+// ExecuteFromChan 从 ch 逐个读取工作项，用 f 创建任务并立即以组的配置开始执行，结果按完成顺序发送到返回的通道，
+// 用于把基于通道的流水线接入任务组；f 返回 nil 时跳过该工作项，不使用 AddTask 添加的任务
+//
+// 每个工作项的任务是一次独立的执行，组的限制按工作项分别生效：WithMaxConcurrency、WithRetryBudget、WithComputeBudget
+// 等不在工作项之间共享，等待时长从该任务开始计时，超时的任务走超时处理、不发送结果，WithDefer、WithSummaryLog 等按执行生效的选项
+// 对每个任务分别生效，组级错误（如 ErrAllFailed）不单独报告；同 ExecuteTasks，Running、CancelTask、SnapshotResults
+// 针对的是最近开始的工作项。需要所有工作项共享限制时用 AddTasksFromChan 添加任务后执行。
+// 无论是否设置 WithCollectRet 都会发送结果；配置错误（如负的等待时长）时只发送一个 Error 为该错误的结果后关闭通道，
+// 不检查通过 AddTask 添加的任务的依赖
+//
+// 结束语义：ch 关闭或组上下文（WithCtx、WithContext）结束后不再读取工作项，已开始的任务全部结束后关闭结果通道；
+// ch 永不关闭且组上下文不结束时结果通道也不会关闭，读取方应以其他方式退出。结果通道没有缓存，
+// 调用方需读取到通道关闭，否则任务会阻塞在发送结果上
+func ExecuteFromChan[T any](tg *Group, ch <-chan T, f func(T) Tasker) <-chan Result {
+	out := make(chan Result)
+
+	tg.mu.Lock()
+	var err error
+	if tg.timeout < 0 {
+		err = tg.durationError()
+	}
+	var done <-chan struct{}
+	if tg.ctx != nil {
+		done = tg.ctx.Done()
+	}
+	tg.mu.Unlock()
+	if err != nil {
+		go func() {
+			out <- Result{Error: err, Status: StatusFailed}
+			close(out)
+		}()
+		return out
+	}
+
+	go func() {
+		var wg sync.WaitGroup
+		defer close(out)
+		defer wg.Wait()
+
+		for {
+			select {
+			case item, ok := <-ch:
+				if !ok {
+					return
+				}
+				t := f(item)
+				if t == nil {
+					continue
+				}
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for _, r := range tg.executeOne(t) {
+						out <- r
+					}
+				}()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// executeOne 以组的配置单独执行任务 t 并收集结果，不修改通过 AddTask 添加的任务
+func (tg *Group) executeOne(t Tasker) []Result {
+	tg.mu.Lock()
+	saved := tg.swapTasks(taskSet{tasks: []Tasker{t}})
+	ex := tg.newExecution(tg.ctx)
+	ex.collect = true
+	tg.run(ex)
+	tg.swapTasks(saved)
+	tg.mu.Unlock()
+
+	defer ex.cancel()
+	return tg.groupResult(ex, tg.collectResults(ex)).Results
+}
@@ -0,0 +1,124 @@
+package job
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddTasksFromChan(t *testing.T) {
+	as := assert.New(t)
+
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := 1; i <= 4; i++ {
+			ch <- i
+		}
+	}()
+
+	var running, peak int32
+	tg := NewTaskGroup("add_from_chan", WithDuration(time.Second), WithCollectRet(), WithMaxConcurrency(1))
+	n := AddTasksFromChan(tg, ch, func(i int) Tasker {
+		if i == 3 {
+			return nil
+		}
+		return TaskFunc(func() (interface{}, error) {
+			if cur := atomic.AddInt32(&running, 1); cur > atomic.LoadInt32(&peak) {
+				atomic.StoreInt32(&peak, cur)
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return i * 10, nil
+		})
+	})
+	as.Equal(3, n)
+
+	results, err := tg.Execute()
+	as.NoError(err)
+	as.ElementsMatch([]Result{{Value: 10}, {Value: 20}, {Value: 40}}, results)
+	as.EqualValues(1, atomic.LoadInt32(&peak)) // 所有工作项共享并发限制
+}
+
+func TestExecuteFromChan(t *testing.T) {
+	as := assert.New(t)
+
+	ch := make(chan int)
+	tg := NewTaskGroup("from_chan", WithDuration(time.Second))
+	tg.AddTask(newTestSt("template", 0, true)) // 不执行已添加的任务
+	out := ExecuteFromChan(tg, ch, func(i int) Tasker {
+		if i == 3 {
+			return nil
+		}
+		return TaskFunc(func() (interface{}, error) { return i * 10, nil })
+	})
+
+	// 工作项到达即开始执行，不等通道关闭
+	ch <- 1
+	select {
+	case r := <-out:
+		as.Equal(Result{Value: 10}, r)
+	case <-time.After(time.Second):
+		as.Fail("result not streamed")
+	}
+
+	go func() {
+		defer close(ch)
+		for i := 2; i <= 4; i++ {
+			ch <- i
+		}
+	}()
+	var results []Result
+	for r := range out {
+		results = append(results, r)
+	}
+	as.ElementsMatch([]Result{{Value: 20}, {Value: 40}}, results)
+
+	// 不检查已添加任务的依赖
+	tg.AddDependentTask("broken", []string{"missing"}, func(map[string]Result) Tasker { return nil })
+	ch = make(chan int, 1)
+	ch <- 5
+	close(ch)
+	out = ExecuteFromChan(tg, ch, func(i int) Tasker {
+		return TaskFunc(func() (interface{}, error) { return i, nil })
+	})
+	results = nil
+	for r := range out {
+		results = append(results, r)
+	}
+	as.Equal([]Result{{Value: 5}}, results)
+}
+
+func TestExecuteFromChanTermination(t *testing.T) {
+	as := assert.New(t)
+
+	// 通道不关闭时组上下文结束后停止读取，已开始的任务结束后关闭结果通道
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan int)
+	tg := NewTaskGroup("from_chan_ctx", WithCtx(ctx))
+	out := ExecuteFromChan(tg, ch, func(i int) Tasker {
+		return TaskFunc(func() (interface{}, error) {
+			time.Sleep(20 * time.Millisecond)
+			return i, nil
+		})
+	})
+	ch <- 1
+	cancel()
+	var results []Result
+	for r := range out {
+		results = append(results, r)
+	}
+	as.LessOrEqual(len(results), 1)
+
+	// 配置错误只发送一个错误结果
+	bad := NewTaskGroup("from_chan_bad", WithDuration(-time.Second))
+	out = ExecuteFromChan(bad, make(chan int), func(int) Tasker { return nil })
+	r, ok := <-out
+	as.True(ok)
+	as.ErrorIs(r.Error, ErrNegativeDuration)
+	_, ok = <-out
+	as.False(ok)
+}
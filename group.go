@@ -3,10 +3,13 @@ package job
 import (
 	"bytes"
 	"context"
-	"errors"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"math"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -18,13 +21,50 @@ import (
 */
 
 type Result struct {
-	Value interface{}
-	Error error
+	Value         interface{}
+	Error         error
+	Status        TaskStatus
+	OriginalError error // 使用兜底值时任务原本的错误（失败、超时或 panic），此时 Error 为 nil
+	Stage         int   // AddStagedTask 指定的阶段，其他任务为 0
 }
 
+// cause 返回导致结果失败的错误
+func (r Result) cause() error {
+	if r.Error != nil {
+		return r.Error
+	}
+	return r.OriginalError
+}
+
+// GroupResult 一次执行的最终结果，ExecChan 返回的通道上只会发送一次
+//
+// Error 的全部可能取值：
+//   - nil：执行完成（包括部分任务超时，超时任务不计入 Results，由 TaskTimeout 处理）
+//   - ErrNoTasks：组内没有任务（设置 WithAllowEmpty 时返回空结果，Error 为 nil）
+//   - ErrNoTimeoutForCollect：收集结果但未设置等待时长
+//   - ErrNegativeDuration：WithDuration 设置了负数
+//   - 任务依赖配置错误：任务重名、依赖不存在或循环依赖
+//   - ErrAllFailed：设置了 WithRequireAnySuccess 且没有任务成功，同时包装各任务的错误
+//   - ErrStalled：超过 WithStallTimeout 没有任何任务完成，任务组被取消
+//
+// 新增的终止条件必须在唯一一次发送前写入 Error
 type GroupResult struct {
-	Results []Result
-	Error   error
+	Results   []Result
+	Error     error
+	Stats     Stats
+	Aggregate interface{} // WithAggregator 折叠全部已交付结果得到的值
+	RunID     string      // WithRunID 设置或生成的本次执行 ID
+	// CancelReason 收集方停止等待未结束任务的原因，没有提前停止时为 NotCanceled；
+	// 主动取消时任务上下文的 context.Cause 为对应的 *CancelError
+	CancelReason CancelReason
+	// NotStarted 收集结束时从未开始执行的任务下标（排队时被取消、依赖失败被跳过、超过计算预算等），
+	// 区别于开始执行后超时的任务；NotStartedNames 为其中命名任务的名称；未启动执行（配置错误）时为 nil
+	NotStarted      []int
+	NotStartedNames []string
+	// TimeoutResults 超时（或被取消）的任务在收集结束时补上的兜底值，按任务下标排列，与 Results 中对应的结果相同，
+	// 便于单独查看哪些任务用了补偿值；只包含截止或取消时仍未结束的任务，没有兜底值的超时任务和截止前 panic 的任务不在其中。TimeoutHandler 没有返回值，
+	// 且可能在收集结束后才被调用，其产出无法收集，需要补偿值时使用 AddTaskWithDefault 或 WithDefaultOnTimeout
+	TimeoutResults []TimeoutResult
 }
 
 // Tasker 定义任务接口
@@ -36,12 +76,43 @@ type TaskTimeout interface {
 	TimeoutHandler(ret interface{}, err error)
 }
 
+// TaskTimeoutCtx 带上下文的超时处理，任务同时实现 TaskTimeout 时只调用 TimeoutHandlerCtx
+// ctx 保留任务上下文的值但不会因任务超时而已被取消，设置 WithHandlerTimeout 时在其时限后取消，
+// 处理器应据此限制自身的清理耗时
+type TaskTimeoutCtx interface {
+	TimeoutHandlerCtx(ctx context.Context, ret interface{}, err error)
+}
+
+// DeadlineTasker 自带截止时间的任务，实际截止时间取任务截止时间与组截止时间（WithDuration）中较早者
+// 到期后组不再等待该任务，其结果走超时处理；未实现该接口的任务只受组截止时间约束
+// 无等待时长（异步模式）时组不等待任何任务，截止时间不影响返回
+type DeadlineTasker interface {
+	Deadline() time.Time
+}
+
 type TaskFunc func() (interface{}, error)
 
 func (f TaskFunc) Execute() (interface{}, error) {
 	return f()
 }
 
+// ContextTasker 感知上下文的任务，执行时传入该任务独立的上下文
+// 任务超时、被 CancelTask 取消或组结束时上下文被取消
+type ContextTasker interface {
+	Tasker
+	ExecuteCtx(ctx context.Context) (interface{}, error)
+}
+
+type TaskFuncCtx func(ctx context.Context) (interface{}, error)
+
+func (f TaskFuncCtx) Execute() (interface{}, error) {
+	return f(context.Background())
+}
+
+func (f TaskFuncCtx) ExecuteCtx(ctx context.Context) (interface{}, error) {
+	return f(ctx)
+}
+
 type Logger interface {
 	Info(message string, data map[string]interface{})
 	Error(message string, err error, data map[string]interface{})
@@ -62,10 +133,65 @@ type Option interface {
 }
 
 type options struct {
-	Log        Logger
-	Duration   time.Duration
-	CollectRet bool
-	Ctx        context.Context
+	Log         Logger
+	Duration    time.Duration
+	CollectRet  bool
+	Ctx         context.Context
+	Heartbeat   time.Duration
+	RetChanSize int
+	ErrTimeout  bool
+
+	DependencyPolicy           DependencyPolicy
+	LogSampling                float64
+	Sink                       func(Result)
+	OnPanic                    func(index int, recovered interface{}, stack []byte)
+	MetricsHook                func(TaskMetric)
+	RequireAnySuccess          bool
+	LogContextExtractor        func(context.Context) map[string]interface{}
+	Middleware                 []func(Tasker) Tasker
+	OrderedSink                func(int, Result)
+	MaxConcurrency             int
+	TimeoutFromStart           bool
+	ResultFilter               func(Result) bool
+	StartGate                  <-chan struct{}
+	EagerCancel                bool
+	DefaultOnTimeout           func(int) interface{}
+	Sequential                 bool
+	Aggregator                 aggregatorOption
+	AllowEmpty                 bool
+	MaxExtension               time.Duration
+	DedupeTasks                bool
+	HandlerTimeout             time.Duration
+	QueueTimeout               time.Duration
+	RunID                      runIDOption
+	Runner                     Runner
+	AdaptiveConcurrency        adaptiveConcurrencyOption
+	LateResults                bool
+	CostOrdering               bool
+	PausedTimeExcluded         bool
+	Retries                    int
+	RetryBudget                int
+	ComputeBudget              time.Duration
+	ComputeBudgetCancelRunning bool
+	Synchronous                bool
+	RetryBackoff               time.Duration
+	BoundaryPolicy             BoundaryPolicy
+	Clock                      Clock
+	SendStallTimeout           time.Duration
+	StagedExecution            bool
+	StageFailFast              bool
+	MaxResultBytes             int
+	PerTaskTimeoutFraction     float64
+	Bulkheads                  map[string]int
+	ResultDedup                func(Result) string
+	SummaryLog                 bool
+	Defers                     []func()
+	CloneTasks                 bool
+	StallTimeout               time.Duration
+	Classify                   func(TaskStatus) Outcome
+	Meter                      Meter
+	MeterFromContext           bool
+	Recoverer                  func(int, interface{}, []byte) Result
 }
 
 type logOption struct {
@@ -96,12 +222,155 @@ func (c ctxOption) bind(o *options) {
 	o.Ctx = c.ctx
 }
 
+type heartbeatOption time.Duration
+
+func (h heartbeatOption) bind(o *options) {
+	o.Heartbeat = time.Duration(h)
+}
+
+type retChanSizeOption int
+
+func (r retChanSizeOption) bind(o *options) {
+	o.RetChanSize = int(r)
+}
+
+type errTimeoutOption bool
+
+func (e errTimeoutOption) bind(o *options) {
+	o.ErrTimeout = bool(e)
+}
+
+type logSamplingOption float64
+
+func (l logSamplingOption) bind(o *options) {
+	o.LogSampling = float64(l)
+}
+
+type sinkOption func(Result)
+
+func (s sinkOption) bind(o *options) {
+	o.Sink = s
+}
+
+type onPanicOption func(int, interface{}, []byte)
+
+func (p onPanicOption) bind(o *options) {
+	o.OnPanic = p
+}
+
+type requireAnySuccessOption bool
+
+func (r requireAnySuccessOption) bind(o *options) {
+	o.RequireAnySuccess = bool(r)
+}
+
+type logContextExtractorOption func(context.Context) map[string]interface{}
+
+func (l logContextExtractorOption) bind(o *options) {
+	o.LogContextExtractor = l
+}
+
+type middlewareOption func(Tasker) Tasker
+
+func (m middlewareOption) bind(o *options) {
+	o.Middleware = append(o.Middleware, m)
+}
+
+type orderedSinkOption func(int, Result)
+
+func (s orderedSinkOption) bind(o *options) {
+	o.OrderedSink = s
+}
+
+type maxConcurrencyOption int
+
+func (m maxConcurrencyOption) bind(o *options) {
+	o.MaxConcurrency = int(m)
+}
+
+type timeoutFromStartOption bool
+
+func (t timeoutFromStartOption) bind(o *options) {
+	o.TimeoutFromStart = bool(t)
+}
+
+type resultFilterOption func(Result) bool
+
+func (r resultFilterOption) bind(o *options) {
+	o.ResultFilter = r
+}
+
+type startGateOption <-chan struct{}
+
+func (s startGateOption) bind(o *options) {
+	o.StartGate = (<-chan struct{})(s)
+}
+
+type eagerCancelOption bool
+
+func (e eagerCancelOption) bind(o *options) {
+	o.EagerCancel = bool(e)
+}
+
+type defaultOnTimeoutOption func(int) interface{}
+
+func (d defaultOnTimeoutOption) bind(o *options) {
+	o.DefaultOnTimeout = d
+}
+
+type sequentialOption bool
+
+func (s sequentialOption) bind(o *options) {
+	o.Sequential = bool(s)
+}
+
+type aggregatorOption struct {
+	fold    func(acc interface{}, r Result) interface{}
+	initial interface{}
+}
+
+func (a aggregatorOption) bind(o *options) {
+	o.Aggregator = a
+}
+
+type allowEmptyOption bool
+
+func (a allowEmptyOption) bind(o *options) {
+	o.AllowEmpty = bool(a)
+}
+
+type maxExtensionOption time.Duration
+
+func (m maxExtensionOption) bind(o *options) {
+	o.MaxExtension = time.Duration(m)
+}
+
+type dedupeTasksOption bool
+
+func (d dedupeTasksOption) bind(o *options) {
+	o.DedupeTasks = bool(d)
+}
+
+type handlerTimeoutOption time.Duration
+
+func (h handlerTimeoutOption) bind(o *options) {
+	o.HandlerTimeout = time.Duration(h)
+}
+
+type queueTimeoutOption time.Duration
+
+func (q queueTimeoutOption) bind(o *options) {
+	o.QueueTimeout = time.Duration(q)
+}
+
 func WithLog(log Logger) Option {
 	return logOption{
 		Log: log,
 	}
 }
 
+// WithDuration 设置等待时长，从开始执行任务组时计时；0 表示没有等待时长（默认，不等待、异步执行），
+// 负数是配置错误，执行时返回包装了 ErrNegativeDuration 的错误，而不是立即超时或静默地异步执行
 func WithDuration(d time.Duration) Option {
 	return durationOption(d)
 }
@@ -116,12 +385,149 @@ func WithCollectRet() Option {
 	return collectRetOption(true)
 }
 
+// WithHeartbeat 等待期间每隔 interval 通过 Logger 输出一次进度（已完成/总数/已耗时）
+// 仅在有等待时长时生效，定时器随执行结束停止
+func WithHeartbeat(interval time.Duration) Option {
+	return heartbeatOption(interval)
+}
+
+// WithErrorTriggersTimeoutHandler 任务返回非 nil 错误时也调用其 TimeoutHandler，统一失败与超时的补偿逻辑
+// 结果仍正常收集
+func WithErrorTriggersTimeoutHandler() Option {
+	return errTimeoutOption(true)
+}
+
+// WithLogSampling 任务 panic 日志按 rate（0~1）确定性采样输出，其余只计数，
+// 全部任务结束后输出一条汇总日志；rate 不在 (0, 1) 内时全部输出
+func WithLogSampling(rate float64) Option {
+	return logSamplingOption(rate)
+}
+
+// WithSink 每个结果到达时在收集协程中依次调用 sink，适合流式处理结果
+// 超时或取消时已入队的结果也会交给 sink；仅在有等待时长时生效，与 WithCollectRet 可同时使用
+func WithSink(sink func(Result)) Option {
+	return sinkOption(sink)
+}
+
+// WithOnPanic 任务 panic 时调用 fn（日志照常输出），index 为任务下标，recovered 为 recover() 的原始值
+// fn 在任务协程中调用，其自身的 panic 会被恢复并记录日志
+func WithOnPanic(fn func(index int, recovered interface{}, stack []byte)) Option {
+	return onPanicOption(fn)
+}
+
+// WithRequireAnySuccess 没有任何任务成功（全部失败、跳过、panic 或超时）时 GroupResult.Error 为 ErrAllFailed，
+// 并包装各任务的错误，Results 照常返回；异步执行不等待任务，不做此判断
+// 各状态按 WithClassify 归类，默认只有 StatusSucceeded 算成功
+func WithRequireAnySuccess() Option {
+	return requireAnySuccessOption(true)
+}
+
+// WithLogContextExtractor 每次执行开始时用 fn 从组上下文中提取字段（如 trace id），
+// 合并到本次执行输出的每条日志 data 中，data 中已有的同名字段优先
+func WithLogContextExtractor(fn func(ctx context.Context) map[string]interface{}) Option {
+	return logContextExtractorOption(fn)
+}
+
+// WithMiddleware 为每个任务套上中间件，可多次使用，先注册的在最外层
+// 中间件在任务协程中组装和执行，其 panic 与任务 panic 一样被恢复；超时处理仍调用原任务，
+// 包装后的任务需实现 ContextTasker（或 Unwrap）才能拿到任务上下文
+func WithMiddleware(mw func(next Tasker) Tasker) Option {
+	return middlewareOption(mw)
+}
+
+// WithOrderedSink 按任务下标顺序回调结果：先完成的后序结果暂存，直到前面的任务全部交付后依次回调，
+// 暂存的结果最多为任务数；中间某个任务很慢时会阻塞其后所有回调，这是有意为之
+// 超时或取消时剩余暂存结果按下标顺序回调，跳过没有交付结果的任务
+func WithOrderedSink(sink func(index int, r Result)) Option {
+	return orderedSinkOption(sink)
+}
+
+// WithResultFilter 只收集 keep 返回 true 的结果，丢弃的结果不占用 Results 的内存，
+// 但仍计入 Stats，也照常交给 sink
+func WithResultFilter(keep func(Result) bool) Option {
+	return resultFilterOption(keep)
+}
+
+// WithEagerCancel 组不再等待任何任务时（所有任务结束、超时或被取消）立即取消组上下文，
+// 尽早释放计时器并通知仍在运行的子操作；默认在收集结束、发送最终结果后才取消
+func WithEagerCancel() Option {
+	return eagerCancelOption(true)
+}
+
+// WithAggregator 以 initial 为初始值，收集协程对每个已交付的结果调用 fold 折叠，最终值放在 GroupResult.Aggregate
+// 只需要汇总值时可不设置 WithCollectRet，避免保存全部结果；被 WithResultFilter 过滤的结果同样参与折叠
+// 结果按完成顺序折叠，顺序不确定，需要确定顺序时请使用 WithOrderedSink 自行折叠
+func WithAggregator(fold func(acc interface{}, r Result) interface{}, initial interface{}) Option {
+	return aggregatorOption{fold: fold, initial: initial}
+}
+
+// WithAllowEmpty 没有任务时视为执行成功，返回空结果而不是 ErrNoTasks
+func WithAllowEmpty() Option {
+	return allowEmptyOption(true)
+}
+
+// WithHandlerTimeout 为 TaskTimeoutCtx 的超时处理设置时限，默认不限制
+func WithHandlerTimeout(d time.Duration) Option {
+	return handlerTimeoutOption(d)
+}
+
+// WithRunID 为每次执行设置关联 ID，写入本次执行的日志（run_id）、TaskMetric.RunID 和 GroupResult.RunID，
+// 用于串联一次执行的全部日志和指标；id 为空时每次执行自动生成新的 ID
+func WithRunID(id string) Option {
+	return runIDOption{enabled: true, id: id}
+}
+
+type runIDOption struct {
+	enabled bool
+	id      string
+}
+
+func (r runIDOption) bind(o *options) {
+	o.RunID = r
+}
+
+// next 返回本次执行的关联 ID，未启用时为空，未指定时生成新的 ID
+func (r runIDOption) next() string {
+	if !r.enabled || r.id != "" {
+		return r.id
+	}
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// WithResultChanSize 设置结果通道的缓存大小，默认与任务数相同
+// 缓存越小占用内存越少，但任务完成后需等待收集协程取走结果（超时仍会放弃发送），
+// 大任务组且不收集结果时可设置为 0 或较小的值
+func WithResultChanSize(n int) Option {
+	return retChanSizeOption(n)
+}
+
+// OptionSet 一组可复用的默认配置，本身也是 Option，按顺序绑定
+type OptionSet []Option
+
+func (s OptionSet) bind(o *options) {
+	for _, opt := range s {
+		opt.bind(o)
+	}
+}
+
+// NewTaskGroupWithDefaults 以 defaults 为基础创建任务组，overrides 在其后绑定，后者覆盖前者
+func NewTaskGroupWithDefaults(name string, defaults OptionSet, overrides ...Option) *Group {
+	opts := make([]Option, 0, len(overrides)+1)
+	opts = append(opts, defaults)
+	opts = append(opts, overrides...)
+	return NewTaskGroup(name, opts...)
+}
+
 // NewTaskGroup 创建一个新的任务组
 func NewTaskGroup(name string, opts ...Option) *Group {
 	defaultOptions := options{
-		Log:        &defaultLog{},
-		Duration:   0,
-		CollectRet: false,
+		Log:         &defaultLog{},
+		Duration:    0,
+		CollectRet:  false,
+		RetChanSize: -1,
+		RetryBudget: -1,
 	}
 
 	for _, opt := range opts {
@@ -135,6 +541,61 @@ func NewTaskGroup(name string, opts ...Option) *Group {
 		collectResult: defaultOptions.CollectRet,
 		log:           defaultOptions.Log,
 		ctx:           defaultOptions.Ctx,
+		heartbeat:     defaultOptions.Heartbeat,
+		retChanSize:   defaultOptions.RetChanSize,
+
+		errorTriggersTimeout:       defaultOptions.ErrTimeout,
+		dependencyPolicy:           defaultOptions.DependencyPolicy,
+		logSampling:                defaultOptions.LogSampling,
+		sink:                       defaultOptions.Sink,
+		onPanic:                    defaultOptions.OnPanic,
+		metricsHook:                defaultOptions.MetricsHook,
+		requireAnySuccess:          defaultOptions.RequireAnySuccess,
+		logContextExtractor:        defaultOptions.LogContextExtractor,
+		middleware:                 defaultOptions.Middleware,
+		orderedSink:                defaultOptions.OrderedSink,
+		maxConcurrency:             defaultOptions.MaxConcurrency,
+		timeoutFromStart:           defaultOptions.TimeoutFromStart,
+		resultFilter:               defaultOptions.ResultFilter,
+		startGate:                  defaultOptions.StartGate,
+		eagerCancel:                defaultOptions.EagerCancel,
+		defaultOnTimeout:           defaultOptions.DefaultOnTimeout,
+		sequential:                 defaultOptions.Sequential,
+		aggregator:                 defaultOptions.Aggregator,
+		allowEmpty:                 defaultOptions.AllowEmpty,
+		maxExtension:               defaultOptions.MaxExtension,
+		dedupeTasks:                defaultOptions.DedupeTasks,
+		handlerTimeout:             defaultOptions.HandlerTimeout,
+		queueTimeout:               defaultOptions.QueueTimeout,
+		runID:                      defaultOptions.RunID,
+		runner:                     defaultOptions.Runner,
+		adaptiveConcurrency:        defaultOptions.AdaptiveConcurrency,
+		lateResults:                defaultOptions.LateResults,
+		costOrdering:               defaultOptions.CostOrdering,
+		pausedTimeExcluded:         defaultOptions.PausedTimeExcluded,
+		retries:                    defaultOptions.Retries,
+		retryBudget:                defaultOptions.RetryBudget,
+		computeBudget:              defaultOptions.ComputeBudget,
+		computeBudgetCancelRunning: defaultOptions.ComputeBudgetCancelRunning,
+		synchronous:                defaultOptions.Synchronous,
+		retryBackoff:               defaultOptions.RetryBackoff,
+		boundaryPolicy:             defaultOptions.BoundaryPolicy,
+		timeSource:                 defaultOptions.Clock,
+		sendStallTimeout:           defaultOptions.SendStallTimeout,
+		stagedExecution:            defaultOptions.StagedExecution,
+		stageFailFast:              defaultOptions.StageFailFast,
+		maxResultBytes:             defaultOptions.MaxResultBytes,
+		perTaskTimeoutFraction:     defaultOptions.PerTaskTimeoutFraction,
+		bulkheads:                  defaultOptions.Bulkheads,
+		resultDedup:                defaultOptions.ResultDedup,
+		summaryLog:                 defaultOptions.SummaryLog,
+		defers:                     defaultOptions.Defers,
+		cloneTasks:                 defaultOptions.CloneTasks,
+		stallTimeout:               defaultOptions.StallTimeout,
+		classify:                   defaultOptions.Classify,
+		meter:                      defaultOptions.Meter,
+		meterFromContext:           defaultOptions.MeterFromContext,
+		recoverer:                  defaultOptions.Recoverer,
 	}
 
 	return tg
@@ -143,7 +604,6 @@ func NewTaskGroup(name string, opts ...Option) *Group {
 // Group 任务组结构体
 type Group struct {
 	mu sync.Mutex
-	wg sync.WaitGroup
 
 	name          string
 	tasks         []Tasker
@@ -151,6 +611,83 @@ type Group struct {
 	collectResult bool
 	log           Logger
 	ctx           context.Context
+	heartbeat     time.Duration
+	retChanSize   int
+
+	errorTriggersTimeout       bool
+	dependencyPolicy           DependencyPolicy
+	logSampling                float64
+	sink                       func(Result)
+	onPanic                    func(index int, recovered interface{}, stack []byte)
+	metricsHook                func(TaskMetric)
+	requireAnySuccess          bool
+	logContextExtractor        func(context.Context) map[string]interface{}
+	middleware                 []func(Tasker) Tasker
+	orderedSink                func(int, Result)
+	maxConcurrency             int
+	timeoutFromStart           bool
+	resultFilter               func(Result) bool
+	startGate                  <-chan struct{}
+	eagerCancel                bool
+	defaultOnTimeout           func(int) interface{}
+	sequential                 bool
+	aggregator                 aggregatorOption
+	allowEmpty                 bool
+	maxExtension               time.Duration
+	dedupeTasks                bool
+	handlerTimeout             time.Duration
+	queueTimeout               time.Duration
+	runID                      runIDOption
+	runner                     Runner
+	adaptiveConcurrency        adaptiveConcurrencyOption
+	lateResults                bool
+	costOrdering               bool
+	pausedTimeExcluded         bool
+	retries                    int
+	retryBudget                int
+	computeBudget              time.Duration
+	computeBudgetCancelRunning bool
+	synchronous                bool
+	retryBackoff               time.Duration
+	boundaryPolicy             BoundaryPolicy
+	timeSource                 Clock
+	sendStallTimeout           time.Duration
+	stagedExecution            bool
+	stageFailFast              bool
+	maxResultBytes             int
+	perTaskTimeoutFraction     float64
+	bulkheads                  map[string]int
+	resultDedup                func(Result) string
+	summaryLog                 bool
+	defers                     []func()
+	cloneTasks                 bool
+	stallTimeout               time.Duration
+	classify                   func(TaskStatus) Outcome
+	meter                      Meter
+	meterFromContext           bool
+	recoverer                  func(int, interface{}, []byte) Result
+
+	pause     pauseState              // Pause/Resume 的暂停状态，不受 Reset 影响
+	names     map[int]string          // 命名任务的下标 -> 名称
+	fallbacks map[int]interface{}     // 任务下标 -> 兜底值
+	taskCtxs  map[int]context.Context // 任务下标 -> 任务自己的上下文
+	cleanups  map[int]func()          // 任务下标 -> 清理函数
+	stages    map[int]int             // 任务下标 -> 阶段
+	cur       *execution              // 最近一次执行
+}
+
+// Name 返回任务组名称
+func (tg *Group) Name() string {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	return tg.name
+}
+
+// SetName 修改任务组名称，只影响之后开始的执行
+func (tg *Group) SetName(name string) {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	tg.name = name
 }
 
 func (tg *Group) AddTask(t Tasker) {
@@ -167,24 +704,125 @@ func (tg *Group) AddTaskFunc(fn TaskFunc) {
 	tg.AddTasks([]Tasker{fn})
 }
 
+// Add 同 AddTask，返回任务组本身用于链式调用：NewTaskGroup("x").Add(t1).Add(t2).Execute()
+func (tg *Group) Add(t Tasker) *Group {
+	tg.AddTask(t)
+	return tg
+}
+
+// AddFn 同 AddTaskFunc，返回任务组本身用于链式调用
+func (tg *Group) AddFn(fn TaskFunc) *Group {
+	tg.AddTaskFunc(fn)
+	return tg
+}
+
+// CancelTask 取消当前执行中下标为 i 的任务，该任务的上下文被取消、结果走超时处理，不影响其他任务
+// 没有执行中的任务或下标越界时返回 false
+func (tg *Group) CancelTask(i int) bool {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+
+	if tg.cur == nil || i < 0 || i >= len(tg.cur.cancels) {
+		return false
+	}
+	tg.cur.cancels[i]()
+	return true
+}
+
+// SnapshotResults 返回最近一次执行到目前为止已收集结果的副本，不影响执行；
+// 只在收集结果时有效（WithCollectRet、ExecuteDeadline 等），否则返回 nil，执行结束后返回全部收集的结果
+func (tg *Group) SnapshotResults() []Result {
+	tg.mu.Lock()
+	ex := tg.cur
+	tg.mu.Unlock()
+
+	if ex == nil || !ex.collect {
+		return nil
+	}
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+	return append([]Result{}, ex.collected...)
+}
+
+// Running 返回最近一次执行中正在执行的任务下标（升序），没有执行过时返回 nil
+// 等待依赖或执行空位的任务不算正在执行，可结合任务名称定位卡住的任务
+func (tg *Group) Running() []int {
+	tg.mu.Lock()
+	ex := tg.cur
+	tg.mu.Unlock()
+
+	if ex == nil {
+		return nil
+	}
+	var running []int
+	for i := range ex.running {
+		if atomic.LoadInt32(&ex.running[i]) == 1 {
+			running = append(running, i)
+		}
+	}
+	return running
+}
+
+func (tg *Group) AddTaskFuncCtx(fn TaskFuncCtx) {
+	tg.AddTasks([]Tasker{fn})
+}
+
+// Reset 清空任务及其名称、兜底值和上下文，释放任务列表占用的内存，适合任务组长期闲置或任务数差异很大的场景
 func (tg *Group) Reset() {
 	tg.mu.Lock()
 	defer tg.mu.Unlock()
 
 	tg.tasks = nil
+	tg.names = nil
+	tg.fallbacks = nil
+	tg.taskCtxs = nil
+	tg.cleanups = nil
+	tg.stages = nil
+	tg.ctx = nil
+}
+
+// ResetKeep 与 Reset 相同，但保留任务列表和相关 map 已分配的容量，
+// 适合在循环（如基准测试）中反复添加数量相近的任务，避免每轮重新分配
+func (tg *Group) ResetKeep() {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+
+	clear(tg.tasks) // 不再引用旧任务，便于回收
+	tg.tasks = tg.tasks[:0]
+	clear(tg.names)
+	clear(tg.fallbacks)
+	clear(tg.taskCtxs)
+	clear(tg.cleanups)
+	clear(tg.stages)
 	tg.ctx = nil
 }
 
 func (tg *Group) WithContext(ctx context.Context) {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
 	tg.ctx = ctx
 }
 
+// Context 返回任务组正在使用的上下文：执行中（仍有任务未结束）时为本次执行的组上下文，带组的截止时间，
+// 可用于派生子上下文或检查截止时间；不在执行中时返回 WithCtx / WithContext 设置的上下文，都没有时返回 context.Background()
+func (tg *Group) Context() context.Context {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+
+	if ex := tg.cur; ex != nil && int(atomic.LoadInt32(&ex.ended)) < ex.total {
+		return ex.ctx
+	}
+	if tg.ctx != nil {
+		return tg.ctx
+	}
+	return context.Background()
+}
+
 func (tg *Group) isTimeout() bool {
 	return tg.timeout > 0
 }
 
-func (tg *Group) takeContext() (context.Context, context.CancelFunc) {
-	bc := tg.ctx
+func (tg *Group) takeContext(bc context.Context) (context.Context, context.CancelFunc) {
 	if bc == nil {
 		bc = context.Background()
 	}
@@ -199,115 +837,910 @@ func (tg *Group) takeContext() (context.Context, context.CancelFunc) {
 
 func (tg *Group) check() error {
 	if len(tg.tasks) == 0 {
-		return errors.New("no tasks to execute")
+		return tg.configError(ErrNoTasks)
+	}
+
+	if tg.timeout < 0 {
+		return tg.durationError()
 	}
 
 	if tg.collectResult && !tg.isTimeout() {
-		return errors.New("no timeout set for result collection")
+		return tg.configError(ErrNoTimeoutForCollect)
+	}
+
+	if err := tg.checkDependencies(); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+// checkRun 检查与是否收集结果无关的执行配置：等待时长和任务依赖
+func (tg *Group) checkRun() error {
+	if tg.timeout < 0 {
+		return tg.durationError()
+	}
+	return tg.checkDependencies()
+}
+
+// durationError WithDuration 为负数时的配置错误
+func (tg *Group) durationError() error {
+	return tg.configError(fmt.Errorf("%w: %v", ErrNegativeDuration, tg.timeout))
+}
+
+// configError 包装配置错误并带上组名，调用方可用 errors.Is 判断
+func (tg *Group) configError(err error) error {
+	return fmt.Errorf("task group %q: %w", tg.name, err)
+}
+
 // Execute 执行所有任务
 func (tg *Group) Execute() ([]Result, error) {
 	grs := <-tg.ExecChan()
 	return grs.Results, grs.Error
 }
 
+// ExecuteDeadline 以 ctx 为父上下文执行所有任务并收集结果，最迟在截止时间返回
+// 截止时间取 ctx 截止时间与 WithDuration 中较早者，二者都未设置时返回包装了 ErrNoDeadline 的错误
+// complete 为 false 表示截止时仍有任务未完成，results 只包含截止前完成的任务
+// 无论是否设置 WithCollectRet 都会收集结果，且不会阻塞到截止时间之后
+func (tg *Group) ExecuteDeadline(ctx context.Context) (results []Result, complete bool, err error) {
+	tg.mu.Lock()
+	if len(tg.tasks) == 0 {
+		tg.runEarlyDefers()
+		tg.mu.Unlock()
+		if tg.allowEmpty {
+			return nil, true, nil
+		}
+		return nil, false, tg.configError(ErrNoTasks)
+	}
+	if err := tg.checkRun(); err != nil {
+		tg.runEarlyDefers()
+		tg.mu.Unlock()
+		return nil, false, err
+	}
+	if _, ok := ctx.Deadline(); !ok && !tg.isTimeout() {
+		tg.runEarlyDefers()
+		tg.mu.Unlock()
+		return nil, false, tg.configError(ErrNoDeadline)
+	}
+
+	ex := tg.newExecution(ctx)
+	ex.collect = true
+	tg.run(ex)
+	tg.mu.Unlock()
+
+	defer ex.cancel()
+	grs := tg.groupResult(ex, tg.collectResults(ex))
+	return grs.Results, ex.complete(), grs.Error
+}
+
+// ExecuteUntil 执行所有任务并收集结果，每收到一个结果就以已收集的全部结果调用 pred，
+// 返回 true 时取消其余任务（走超时处理）并立即返回，之后到达的结果不再收集
+// pred 只在收集协程中按结果到达顺序依次调用，不会并发；无论是否设置 WithCollectRet 都会收集结果
+// 未设置 WithDuration 时等待到 pred 满足或所有任务结束
+func (tg *Group) ExecuteUntil(pred func(results []Result) bool) ([]Result, error) {
+	tg.mu.Lock()
+	if len(tg.tasks) == 0 {
+		tg.runEarlyDefers()
+		tg.mu.Unlock()
+		if tg.allowEmpty {
+			return nil, nil
+		}
+		return nil, tg.configError(ErrNoTasks)
+	}
+	if err := tg.checkRun(); err != nil {
+		tg.runEarlyDefers()
+		tg.mu.Unlock()
+		return nil, err
+	}
+
+	ex := tg.newExecution(tg.ctx)
+	ex.collect = true
+	ex.until = pred
+	tg.run(ex)
+	tg.mu.Unlock()
+
+	defer ex.cancel()
+	grs := tg.groupResult(ex, tg.collectResults(ex))
+	return grs.Results, grs.Error
+}
+
+// ExecChan 异步执行所有任务，返回的通道恰好收到一个 GroupResult 后关闭
+// 通道缓存为 1，调用方不读取时发送方也不会阻塞，执行结束后不会残留协程
 func (tg *Group) ExecChan() <-chan GroupResult {
+	ch, _ := tg.ExecChanWithCancel()
+	return ch
+}
+
+// ExecChanWithCancel 与 ExecChan 相同，额外返回取消本次执行的函数
+// 取消后不再等待未完成的任务，它们的结果走超时处理；重复调用或执行结束后调用都是安全的
+func (tg *Group) ExecChanWithCancel() (<-chan GroupResult, context.CancelFunc) {
 	tg.mu.Lock()
 	defer tg.mu.Unlock()
 
-	ch := make(chan GroupResult, 1)
+	return tg.execChan()
+}
+
+// execChan ExecChanWithCancel 的实现，调用方需持有 tg.mu
+func (tg *Group) execChan() (<-chan GroupResult, context.CancelFunc) {
+	ch := make(chan GroupResult, 1) // 必须有缓存，保证唯一一次发送不阻塞
+	if len(tg.tasks) == 0 && tg.allowEmpty {
+		tg.runEarlyDefers()
+		ch <- tg.earlyResult(nil)
+		close(ch)
+		return ch, func() {}
+	}
 	if err := tg.check(); err != nil {
-		ch <- GroupResult{Error: err}
-		return ch
+		tg.runEarlyDefers()
+		ch <- tg.earlyResult(err)
+		close(ch)
+		return ch, func() {}
 	}
 
-	ctx, cancel := tg.takeContext() // 不主动取消
-	retChan := make(chan Result, len(tg.tasks))
-	tg.wg.Add(len(tg.tasks))
+	ex := tg.newExecution(tg.ctx)
+	ex.async = !tg.isTimeout()
+	tg.run(ex)
 
-	tg.run(ctx, retChan)
+	if ex.async {
+		// 异步执行不等待任务，上下文在所有任务结束后取消
+		ch <- GroupResult{Stats: Stats{Total: ex.total}, RunID: ex.runID}
+		close(ch)
+		return ch, func() { ex.cancelWith(CanceledByCaller) }
+	}
 
-	done := make(chan struct{})
 	go func() {
-		tg.wg.Wait()
-		close(done)
+		defer close(ch)
+		defer ex.cancel()
+
+		ch <- tg.groupResult(ex, tg.collectResults(ex))
 	}()
 
+	return ch, func() { ex.cancelWith(CanceledByCaller) }
+}
+
+// earlyResult 没有启动任务就返回（配置错误、允许的空任务组）时的结果，
+// 与正常执行的结果形状一致：统计的 Total 为任务数，带有本次的 RunID 和折叠初始值
+func (tg *Group) earlyResult(err error) GroupResult {
+	return GroupResult{
+		Error:     err,
+		Stats:     Stats{Total: len(tg.tasks)},
+		Aggregate: tg.aggregator.initial,
+		RunID:     tg.runID.next(),
+	}
+}
+
+// ExecuteWithCallback 异步执行所有任务并立即返回，执行结束后在后台协程中以结果调用 fn
+// fn 恰好调用一次，配置错误时同样以错误调用；fn 的 panic 会被恢复并记录日志
+func (tg *Group) ExecuteWithCallback(fn func(results []Result, err error)) {
+	ch := tg.ExecChan()
+
+	tg.mu.Lock()
+	log, name := tg.log, tg.name
+	tg.mu.Unlock()
+
 	go func() {
-		defer close(ch)
-		defer cancel()
+		defer func() {
+			if r := recover(); r != nil {
+				logError(log, "execute callback error", &PanicError{Value: r}, map[string]interface{}{
+					"name": name,
+				})
+			}
+		}()
+
+		grs := <-ch
+		fn(grs.Results, grs.Error)
+	}()
+}
+
+// execution 单次执行的运行时状态
+type execution struct {
+	name         string // 开始执行时的组名，执行中改名不影响日志
+	runID        string // 本次执行的关联 ID，未设置 WithRunID 时为空
+	ctx          context.Context
+	cancel       context.CancelFunc
+	cancelCause  context.CancelCauseFunc // 以原因取消 ctx，见 cancelWith
+	parent       context.Context         // 创建 ctx 的上级上下文，可能为 nil
+	retChan      chan taskResult
+	done         chan struct{}
+	collect      bool
+	async        bool              // 不等待任务，结果全部走超时处理
+	eagerCancel  bool              // 组不再等待任何任务时立即取消上下文
+	sink         func(Result)      // 结果到达时的回调
+	filter       func(Result) bool // 返回 false 的结果不收集
+	fold         func(interface{}, Result) interface{}
+	acc          interface{}            // 折叠的中间值，只在收集协程中读写
+	stream       func(taskResult) error // 逐个输出结果，失败时取消执行
+	streamErr    error                  // 输出失败的错误，只在收集协程中读写
+	ordered      *orderedSink           // 按任务下标顺序回调，只在收集协程中使用
+	until        func([]Result) bool    // 满足后停止收集并取消其余任务
+	first        *firstWaiter           // WaitFirst 等待的前 n 个结果
+	dedupKey     func(Result) string    // 结果去重的 key，nil 表示不去重
+	seenKeys     map[string]struct{}    // 已收集结果的 key，只在收集协程中读写
+	sem          chan struct{}          // 限制同时执行的任务数，nil 表示不限制
+	bulkheads    []chan struct{}        // 每个任务所属类别（WithBulkhead）的空位，不限制的任务为 nil
+	turns        []turn                 // WithCostOrdering 获取执行空位的顺序，nil 表示不排序
+	queueWaits   []time.Duration        // 每个任务等待执行空位的时长，只由任务自己的协程读写，没有并发限制时为 nil
+	queueTimeout time.Duration          // 等待执行空位的最长时间
+	gate         <-chan struct{}        // 任务开始执行前等待的信号，nil 表示不等待
+	clock        *deadlineTimer         // WithTimeoutFromStart、WithMaxExtension 的计时器
+	pause        *pauseState            // 任务组的暂停状态
+	adaptive     *adaptiveLimit         // WithAdaptiveConcurrency 的并发控制，nil 表示不调整
+	retries      int                    // 每个任务失败后的最大重试次数
+	retryBackoff time.Duration          // 两次尝试之间的等待时长
+	retryBudget  int64                  // 整组剩余的重试次数，负数表示不限制，原子读写
+	budget       *computeBudget         // WithComputeBudget 的累计耗时，nil 表示不限制
+	errGroup     ErrGroup               // RunInErrGroup 提交任务的 errgroup，nil 表示自行启动协程
+	errs         []error                // RunInErrGroup 中每个任务返回的错误，只由任务自己的协程写入
+	boundary     BoundaryPolicy         // 结果与截止同时就绪时的取舍
+	sendStall    time.Duration          // 发送结果阻塞超过该时长时记录日志，0 表示不检测
+	stages       *stagePlan             // 分阶段执行的计划，没有分阶段任务时为 nil
+	maxBytes     int                    // 成功结果的值的字节数上限，0 表示不限制
+	taskFraction float64                // 任务时限占开始执行时组剩余时长的比例，0 表示不设时限
+	timeSource   Clock                  // 判断任务完成时间的时钟，nil 表示系统时钟
+	sealMu       sync.Mutex             // 保护 sealed
+	sealed       bool                   // 收集方已停止等待，截止时刻的结果不再交付
+	late         chan LateResult        // WithLateResults 的迟到结果，所有任务结束后关闭
+	lateFired    []int32                // 任务已走超时处理（截止时或结束时），原子读写
+	group        *Group                 // 注册到 Registry 的任务组，所有任务结束后移除，未注册时为 nil
+
+	ctxs    []context.Context    // 每个任务独立的上下文
+	cancels []context.CancelFunc // 每个任务上下文的取消函数
+	running []int32              // 正在执行的任务标记，原子读写
+	started []int32              // 已开始执行的任务标记，原子读写
+
+	fallbacks map[int]interface{}   // 任务下标 -> 兜底值
+	onTimeout func(int) interface{} // 超时任务的默认兜底值
+	cleanups  map[int]func()        // 任务下标 -> 清理函数
+	delivered []bool                // 已交付结果的任务，只在收集协程中读写
+	timeouts  []TimeoutResult       // 收集结束时补上的兜底值，只在收集协程中读写
+
+	slots  map[string]*depSlot // 命名任务的结果，供依赖任务读取
+	slotOf []*depSlot          // 按任务下标索引的结果槽，未命名任务为 nil
+
+	total    int
+	finished int32    // 组不再等待的任务数
+	ended    int32    // 实际执行结束的任务数
+	outcomes [5]int64 // 没有收集方时按 TaskStatus 统计的任务数，原子读写
+
+	defers    []func() // WithDefer 注册的函数，nil 表示没有
+	deferLeft int32    // 调用 defer 前还需等待的结束事件数，原子读写
+	start     time.Time
+
+	log         Logger
+	logSampling float64
+	logFields   map[string]interface{} // 本次执行从上下文提取的日志字段
+	panics      int64                  // 任务 panic 次数
+	reason      int32                  // 取消原因 CancelReason，原子读写
+	summary     bool                   // 结束时输出汇总日志
+	meter       Meter                  // 上报计数指标，nil 表示不上报
+	dropped     int64                  // 因超过上限丢弃的结果值的字节数，原子读写
+	counted     Stats                  // 收集协程统计的已交付结果
+
+	classify func(TaskStatus) Outcome // WithRequireAnySuccess 对任务最终状态的归类
+
+	keepErrors bool    // 保留任务错误用于汇总
+	failures   []error // 已交付结果中的错误，只在收集协程中读写
+	mu         sync.Mutex
+	panicErrs  []error        // 任务 panic 转换的错误，由 mu 保护
+	panicked   map[int]Result // 任务下标 -> 截止前 panic 的结果，由 mu 保护
+	pending    []Result       // 异步执行已结束任务的结果，由 mu 保护
+	collected  []Result       // 已收集的结果，只追加，由 mu 保护，供 SnapshotResults 读取
+	suppressed int64          // 采样丢弃的日志数
+}
+
+// resultChanSize 结果通道的缓存大小，默认与任务数相同，同步执行时忽略 WithResultChanSize
+func (tg *Group) resultChanSize(tasks int) int {
+	if tg.retChanSize < 0 || tg.retChanSize > tasks || tg.synchronous {
+		return tasks
+	}
+	return tg.retChanSize
+}
 
-		if !tg.isTimeout() {
-			cancel()
+// newExecution 以 parent 为父上下文为当前任务列表创建一次执行，调用方需持有 tg.mu
+func (tg *Group) newExecution(parent context.Context) *execution {
+	tg.dedupe()
+
+	var clock *deadlineTimer
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if tg.isTimeout() && (tg.timeoutFromStart || tg.maxExtension > 0 || tg.pausedTimeExcluded) {
+		ctx, clock = withDeadlineTimer(parent, tg.timeout, tg.maxExtension)
+		if tg.pausedTimeExcluded {
+			tg.pause.track(clock)
+		}
+		cancel = clock.stop
+		// WithTimeoutFromStart 时计时推迟到第一个任务开始执行
+		if !tg.timeoutFromStart {
+			clock.start()
 		}
+	} else {
+		ctx, cancel = tg.takeContext(parent) // 不主动取消
+	}
+	ctx, cancelCause := context.WithCancelCause(ctx)
+	ex := &execution{
+		name:         tg.name,
+		runID:        tg.runID.next(),
+		log:          tg.log,
+		ctx:          ctx,
+		cancel:       func() { cancelCause(context.Canceled); cancel() },
+		cancelCause:  cancelCause,
+		parent:       parent,
+		retChan:      make(chan taskResult, tg.resultChanSize(len(tg.tasks))),
+		done:         make(chan struct{}),
+		collect:      tg.collectResult,
+		sink:         tg.sink,
+		ordered:      newOrderedSink(tg.orderedSink, len(tg.tasks)),
+		sem:          newSemaphore(tg.maxConcurrency),
+		queueTimeout: tg.queueTimeout,
+		eagerCancel:  tg.eagerCancel,
+		gate:         tg.startGate,
+		filter:       tg.resultFilter,
+		dedupKey:     tg.resultDedup,
+		fold:         tg.aggregator.fold,
+		acc:          tg.aggregator.initial,
+		clock:        clock,
+		pause:        &tg.pause,
+		retries:      tg.retries,
+		retryBackoff: tg.retryBackoff,
+		boundary:     tg.boundaryPolicy,
+		sendStall:    tg.sendStallTimeout,
+		timeSource:   tg.timeSource,
+		retryBudget:  int64(tg.retryBudget),
+		budget:       tg.newComputeBudget(),
+		maxBytes:     tg.maxResultBytes,
+		taskFraction: tg.taskTimeoutFraction(),
 
-		results := tg.collectResults(ctx, retChan, done)
-		ch <- GroupResult{Results: results}
-	}()
+		logSampling: tg.logSampling,
+		summary:     tg.summaryLog,
+		keepErrors:  tg.requireAnySuccess,
+		classify:    tg.classifier(),
+		meter:       tg.meterFor(parent),
+		total:       len(tg.tasks),
+		start:       time.Now(),
+	}
+	if tg.logContextExtractor != nil {
+		ex.logFields = tg.logContextExtractor(ctx)
+	}
+	if tg.adaptiveConcurrency.max > 0 {
+		ex.sem, ex.adaptive = newAdaptiveLimit(tg.adaptiveConcurrency)
+	}
 
-	return ch
+	return ex
+}
+
+// complete 是否所有任务都已执行结束
+func (ex *execution) complete() bool {
+	return int(atomic.LoadInt32(&ex.ended)) == ex.total
+}
+
+// end 任务实际执行结束时调用一次，最后一个结束的任务输出被采样丢弃的日志数
+func (ex *execution) end() {
+	if int(atomic.AddInt32(&ex.ended, 1)) != ex.total {
+		return
+	}
+	if ex.late != nil {
+		close(ex.late)
+	}
+	if ex.group != nil {
+		Deregister(ex.group)
+	}
+	if ex.selfSummary() {
+		ex.logSummary(ex.outcomeStats())
+	}
+	ex.deferDone()
+	if suppressed := atomic.LoadInt64(&ex.suppressed); suppressed > 0 {
+		ex.logInfo("task errors sampled", map[string]interface{}{
+			"panics":     atomic.LoadInt64(&ex.panics),
+			"suppressed": suppressed,
+		})
+	}
+}
+
+// sampleError 记录一次任务错误，返回是否需要输出日志
+// 按计数确定性采样：第 n 次错误在 ceil(n*rate) 增长时输出，即第 1 次必定输出，之后约每 1/rate 次输出一次
+func (ex *execution) sampleError() bool {
+	n := atomic.AddInt64(&ex.panics, 1)
+	if ex.logSampling <= 0 || ex.logSampling >= 1 {
+		return true
+	}
+	if math.Ceil(float64(n)*ex.logSampling) > math.Ceil(float64(n-1)*ex.logSampling) {
+		return true
+	}
+	atomic.AddInt64(&ex.suppressed, 1)
+	return false
+}
+
+// logInfo 输出 Info 日志，自动带上组名和上下文字段
+func (ex *execution) logInfo(message string, data map[string]interface{}) {
+	ex.withLogFields(data)
+	logInfo(ex.log, message, data)
+}
+
+// logError 输出 Error 日志，自动带上组名和上下文字段
+func (ex *execution) logError(message string, err error, data map[string]interface{}) {
+	ex.withLogFields(data)
+	logError(ex.log, message, err, data)
+}
+
+// withLogFields 为日志 data 补充组名和从上下文提取的字段
+func (ex *execution) withLogFields(data map[string]interface{}) {
+	for k, v := range ex.logFields {
+		if _, ok := data[k]; !ok {
+			data[k] = v
+		}
+	}
+	data["name"] = ex.name
+	if ex.runID != "" {
+		data["run_id"] = ex.runID
+	}
+}
+
+// finish 组不再等待某个任务时调用一次，最后一个任务关闭 done，无需额外的 wg.Wait 协程
+func (ex *execution) finish() {
+	if int(atomic.AddInt32(&ex.finished, 1)) == ex.total {
+		close(ex.done)
+		if ex.async || ex.eagerCancel {
+			ex.cancel()
+		}
+	}
 }
 
 // collectResults 收集结果
-func (tg *Group) collectResults(ctx context.Context, retChan chan Result, done chan struct{}) []Result {
+// 等待期间持续消费 retChan，缓存小于任务数时生产者阻塞形成背压
+func (tg *Group) collectResults(ex *execution) []Result {
+	defer ex.deferDone()
+
+	var tick <-chan time.Time
+	if tg.heartbeat > 0 {
+		ticker := time.NewTicker(tg.heartbeat)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	var stall *time.Timer
+	var stalled <-chan time.Time
+	if tg.stallTimeout > 0 {
+		stall = time.NewTimer(tg.stallTimeout)
+		defer stall.Stop()
+		stalled = stall.C
+	}
+
+	var results []Result
+	if ex.collect {
+		results = make([]Result, 0, cap(ex.retChan))
+	}
+
 	// 等待所有任务完成或超时
-	select {
-	case <-ctx.Done():
-	case <-done:
+wait:
+	for {
+		select {
+		case tr := <-ex.retChan:
+			results = ex.accept(results, tr)
+			if ex.until != nil && ex.until(results) {
+				ex.cancelWith(CanceledByUntil)
+				return ex.settle(results)
+			}
+			ex.reachFirst(results)
+			if stall != nil {
+				stall.Reset(tg.stallTimeout)
+			}
+		case <-stalled:
+			ex.logInfo("task group stalled", map[string]interface{}{
+				"stall_timeout": tg.stallTimeout.String(),
+				"done":          atomic.LoadInt32(&ex.ended),
+				"total":         ex.total,
+			})
+			ex.cancelWith(CanceledByStall)
+			break wait
+		case <-ex.ctx.Done():
+			break wait
+		case <-ex.done:
+			break wait
+		case <-tick:
+			ex.logInfo("task group still running", map[string]interface{}{
+				"done":    atomic.LoadInt32(&ex.ended),
+				"total":   ex.total,
+				"elapsed": time.Since(ex.start).String(),
+			})
+		}
 	}
-	close(retChan)
-	if !tg.collectResult {
-		return nil
+
+	// 不关闭 retChan：截止后仍可能有任务在发送，只取出已经进入缓存的结果
+	// 超时或取消时同样会把已入队的结果交给 sink，不丢失已完成的工作
+	ex.seal()
+	for {
+		select {
+		case tr := <-ex.retChan:
+			results = ex.accept(results, tr)
+		default:
+			return ex.settle(results)
+		}
 	}
-	results := make([]Result, 0, cap(retChan))
-	for result := range retChan {
-		results = append(results, result)
+}
+
+// settle 收集结束时补齐兜底结果并回调有序 sink 中剩余的结果
+func (ex *execution) settle(results []Result) []Result {
+	if ex.ctx.Err() != nil {
+		ex.noteCancel()
 	}
+	results = ex.fillFallbacks(results)
+	ex.flushOrdered()
 	return results
 }
 
-func (tg *Group) run(ctx context.Context, retChan chan Result) {
-	// 启动所有任务
-	for i, task := range tg.tasks {
-		go func(t Tasker, i int) {
-			defer tg.wg.Done()
-			defer func() {
-				if r := recover(); r != nil {
-					stack := debug.Stack()
-					if line := bytes.IndexByte(stack[:], '\n'); line >= 0 {
-						stack = stack[line+1:]
-					}
-
-					panicErr := errors.New(fmt.Sprintf("%v", r))
-					tg.log.Error("task run error", panicErr, map[string]interface{}{
-						"name":  tg.name,
-						"i":     i,
-						"stack": string(stack),
-					})
-				}
-			}()
+// groupResult 汇总收集结束时的最终结果，所有终止错误在这里写入
+func (tg *Group) groupResult(ex *execution, results []Result) GroupResult {
+	grs := GroupResult{Results: results, Stats: ex.stats(), Aggregate: ex.acc, RunID: ex.runID, CancelReason: ex.cancelReason()}
+	if tg.requireAnySuccess && ex.allFailed(grs.Stats) {
+		grs.Error = ex.allFailedError(grs.Stats)
+	}
+	if grs.CancelReason == CanceledByStall && grs.Error == nil {
+		grs.Error = tg.stallError()
+	}
+	grs.NotStarted, grs.NotStartedNames = ex.notStarted()
+	grs.TimeoutResults = ex.timeouts
+	if ex.summary {
+		ex.logSummary(grs.Stats)
+	}
+	return grs
+}
+
+// taskResult 带任务下标的结果
+type taskResult struct {
+	index int
+	Result
+}
+
+// accept 处理一个已完成任务的结果
+func (ex *execution) accept(results []Result, tr taskResult) []Result {
+	r := tr.Result
+	if ex.delivered != nil {
+		ex.delivered[tr.index] = true
+	}
+	ex.counted.count(r)
+	// panic 的错误已在任务协程中记入 panicErrs
+	if err := r.cause(); err != nil && ex.keepErrors && r.Status != StatusPanicked && ex.classify(r.Status) == OutcomeFailure {
+		ex.failures = append(ex.failures, err)
+	}
+	if ex.sink != nil {
+		ex.sink(r)
+	}
+	if ex.ordered != nil {
+		ex.ordered.add(tr)
+	}
+	if ex.fold != nil {
+		ex.acc = ex.fold(ex.acc, r)
+	}
+	if ex.stream != nil && ex.streamErr == nil {
+		if ex.streamErr = ex.stream(tr); ex.streamErr != nil {
+			ex.cancelWith(CanceledByStream)
+		}
+	}
+	if !ex.collect || (ex.filter != nil && !ex.filter(r)) {
+		return results
+	}
+	if ex.duplicate(r) {
+		return results
+	}
+	results = append(results, r)
+	ex.mu.Lock()
+	ex.collected = results
+	ex.mu.Unlock()
+	return results
+}
+
+// run 启动所有任务
+func (tg *Group) run(ex *execution) {
+	tg.cur = ex
+	ex.armDefers(tg.defers)
+	if registered(tg) {
+		ex.group = tg
+	}
+	tg.newSlots(ex)
+	tg.newFallbacks(ex)
+	tg.newCleanups(ex)
+	tg.newLateResults(ex)
+	tg.newStages(ex)
+	ex.ctxs = make([]context.Context, len(tg.tasks))
+	ex.running = make([]int32, len(tg.tasks))
+	ex.started = make([]int32, len(tg.tasks))
+	ex.cancels = make([]context.CancelFunc, len(tg.tasks))
+	tg.newBulkheads(ex)
+	if ex.sem != nil || ex.bulkheads != nil {
+		ex.queueWaits = make([]time.Duration, len(tg.tasks))
+	}
+	tasks := tg.runTasks()
+	for i, task := range tasks {
+		ctx, cancel := tg.taskContext(ex, task)
+		if own, ok := tg.taskCtxs[i]; ok {
+			ctx, cancel = mergeContext(ctx, cancel, own)
+		}
+		if ex.taskFraction > 0 {
+			ctx, cancel = withFractionDeadline(ctx, cancel)
+		}
+		ex.ctxs[i], ex.cancels[i] = ctx, cancel
+	}
+	if !tg.sequential && !tg.synchronous {
+		order := tg.launchOrder(ex)
+		tg.newTurns(ex, order)
+		for _, i := range order {
+			task, ctx := tasks[i], ex.ctxs[i]
+			if ex.errGroup != nil {
+				ex.errGroup.Go(func() error {
+					tg.runTask(ex, ctx, task, i, nil)
+					return ex.taskErr(i)
+				})
+				continue
+			}
+			tg.spawn(func() { tg.runTask(ex, ctx, task, i, nil) })
+		}
+	} else {
+		if ex.errGroup != nil {
+			ex.errGroup.Go(func() error {
+				tg.runSequential(ex, tasks)
+				return ex.sequentialErr()
+			})
+			return
+		}
+		tg.spawn(func() { tg.runSequential(ex, tasks) })
+	}
+}
+
+// taskContext 为单个任务派生独立的上下文，取消它不影响其他任务
+func (tg *Group) taskContext(ex *execution, t Tasker) (context.Context, context.CancelFunc) {
+	if dt, ok := taskAs[DeadlineTasker](t); ok {
+		// 任务截止时间与组截止时间取较早者
+		return context.WithDeadline(ex.ctx, dt.Deadline())
+	}
+	return context.WithCancel(ex.ctx)
+}
+
+// runTask 执行单个任务并输出结果，超时的任务走超时处理
+// cause 不为 nil 时不再执行任务（顺序执行中组超时或被取消后剩余的任务），结果记为超时，以 cause 走超时处理，
+// 结果槽、指标、统计等收尾与执行过的任务相同
+func (tg *Group) runTask(ex *execution, ctx context.Context, t Tasker, i int, cause error) {
+	defer ex.cancels[i]()
 
-			value, err := t.Execute()
+	// 任务上下文结束（截止、被取消）后组不再等待该任务
+	var once sync.Once
+	finish := func() { once.Do(ex.finish) }
+	stop := context.AfterFunc(ctx, finish)
+	defer stop()
 
-			ret := Result{Value: value, Error: err}
-			select {
-			case <-ctx.Done(): // 超时了走超时处理,  优先检查超时，因为 resultChan 有缓存，可能两个同时就绪
-				if out, ok := t.(TaskTimeout); ok {
-					out.TimeoutHandler(ret.Value, ret.Error)
+	defer finish()
+	defer ex.end()
+	if ex.late != nil {
+		defer context.AfterFunc(ctx, func() { tg.timeoutAtDeadline(ex, ctx, t, i) })()
+	}
+	if cleanup, ok := ex.cleanups[i]; ok {
+		defer ex.cleanup(cleanup, i)
+	}
+
+	var ret Result
+	timedOut := false
+	if tg.metricsHook != nil {
+		start := time.Now()
+		defer func() {
+			tg.metricsHook(TaskMetric{
+				Group:     ex.name,
+				RunID:     ex.runID,
+				Index:     i,
+				Labels:    taskLabels(t),
+				Status:    ret.Status,
+				Error:     ret.Error,
+				TimedOut:  timedOut,
+				Duration:  time.Since(start),
+				QueueWait: ex.queueWait(i),
+			})
+		}()
+	}
+	if ex.slotOf != nil && ex.slotOf[i] != nil {
+		defer func() { ex.slotOf[i].publish(slotResult(ctx, ret, timedOut)) }()
+	}
+	if ex.errs != nil {
+		defer func() { ex.recordErr(i, ret) }()
+	}
+	if ex.selfSummary() {
+		defer func() { ex.countOutcome(ret.Status) }()
+	}
+	if ex.meter != nil {
+		defer func() { ex.countMetrics(ctx, ret.Status) }()
+	}
+	if ex.stages != nil {
+		defer func() { ex.stages.end(i, ret) }()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			stack := debug.Stack()
+			if line := bytes.IndexByte(stack[:], '\n'); line >= 0 {
+				stack = stack[line+1:]
+			}
+
+			if tg.recoverer != nil {
+				ret = ex.callRecoverer(tg.recoverer, i, r, stack)
+				if tg.onPanic != nil {
+					ex.callOnPanic(tg.onPanic, i, r, stack)
+				}
+				if ret.Status == StatusPanicked {
+					ex.recordPanic(ctx, i, ret)
 				}
-			default:
+				ex.deliverRecovered(ctx, t, i, ret)
+				return
+			}
+
+			panicErr := &PanicError{Value: r, Stack: stack}
+			ret = Result{Error: panicErr, Status: StatusPanicked}
+			ex.recordPanic(ctx, i, ret)
+			if ex.keepErrors && ex.classify(StatusPanicked) == OutcomeFailure {
+				ex.mu.Lock()
+				ex.panicErrs = append(ex.panicErrs, panicErr)
+				ex.mu.Unlock()
+			}
+			if _, ok := ex.fallbacks[i]; ok && !ex.async {
 				select {
-				case retChan <- ret: // 未超时正常输出
+				case ex.retChan <- ex.output(i, ret):
 				case <-ctx.Done():
-					if out, ok := t.(TaskTimeout); ok {
-						out.TimeoutHandler(ret.Value, ret.Error)
-					}
 				}
 			}
-		}(task, i)
+			if ex.sampleError() {
+				data := map[string]interface{}{
+					"i":     i,
+					"stack": string(stack),
+				}
+				if labels := taskLabels(t); labels != nil {
+					data["labels"] = labels
+				}
+				ex.logError("task run error", panicErr, data)
+			}
+			if tg.onPanic != nil {
+				ex.callOnPanic(tg.onPanic, i, r, stack)
+			}
+		}
+	}()
+
+	if cause != nil {
+		ret, timedOut = Result{Error: cause, Status: StatusTimedOut}, true
+		if ex.late != nil {
+			tg.timeoutAtDeadline(ex, ctx, t, i) // 与截止时的超时处理去重
+		} else {
+			tg.handleTimeout(ctx, t, Result{Error: cause})
+		}
+		return
+	}
+
+	run := t
+	if dt, ok := t.(*dependentTask); ok {
+		run, ret = tg.resolveDependent(ex, ctx, dt)
+	}
+	if run != nil {
+		err := ex.stages.wait(ctx, i)
+		if err == nil {
+			err = ex.waitStart(ctx, i)
+		}
+		if err != nil {
+			ret.Error = err // 前面的阶段失败，或等待开始信号、执行空位时超时，不再执行
+		} else {
+			ex.clock.start()
+			stop := ex.startTaskTimeout(ctx)
+			ret.Value, ret.Error = tg.execute(ex, ctx, run, i)
+			stop()
+		}
+	}
+	ret.Status = statusOf(ret.Error)
+	finishedAt := ex.now()
+
+	// 截止时已走过超时处理，结果作为迟到结果输出
+	if ex.deliverLate(i, ret) {
+		timedOut = true
+		return
+	}
+
+	// 排队超时的任务结果照常交付
+	if ret.Error == ErrQueueTimeout {
+		ret.Status = StatusTimedOut
+		timedOut = true
+		tg.handleQueueTimeout(ctx, run, ret)
+		if ex.async {
+			ex.keepPending(ex.output(i, ret).Result)
+		} else {
+			ex.send(ctx, t, ex.output(i, ret))
+		}
+		return
+	}
+
+	// 超时了走超时处理，优先检查超时，因为 resultChan 有缓存，可能两个同时就绪
+	// 异步执行没有等待方，同样走超时处理；WithBoundaryPolicy(PreferComplete) 时截止前完成的结果仍然交付
+	if ex.async || ctx.Err() != nil {
+		if !ex.async && ex.deliverAtBoundary(ctx, i, ret, finishedAt) {
+			return
+		}
+		timedOut = true
+		tg.handleTimeout(ctx, run, ret)
+		if ex.async {
+			ex.keepPending(ex.output(i, ret).Result)
+		}
+		return
+	}
+	if ex.send(ctx, t, ex.output(i, ret)) { // 未超时正常输出
+		if ret.Error != nil && tg.errorTriggersTimeout {
+			tg.handleTimeout(ctx, run, ret)
+		}
+	} else {
+		timedOut = true
+		tg.handleTimeout(ctx, run, ret)
+	}
+}
+
+// execute 套上中间件执行任务，结束后释放执行空位
+func (tg *Group) execute(ex *execution, ctx context.Context, t Tasker, i int) (interface{}, error) {
+	defer ex.release(i)
+	atomic.StoreInt32(&ex.running[i], 1)
+	ex.markStarted(i)
+	defer atomic.StoreInt32(&ex.running[i], 0)
+
+	exec := tg.wrap(t)
+	start := time.Now()
+	if ex.budget != nil {
+		defer func() { ex.charge(time.Since(start)) }()
+	}
+	var v interface{}
+	var err error
+	for attempt := 0; ; attempt++ {
+		if ct, ok := taskAs[ContextTasker](exec); ok {
+			v, err = ct.ExecuteCtx(ctx)
+		} else {
+			v, err = exec.Execute()
+		}
+		if !ex.retry(ctx, err, attempt) {
+			break
+		}
+	}
+	ex.adapt(ctx, err, time.Since(start))
+	return v, err
+}
+
+// wrap 按注册顺序组装中间件，第一个在最外层
+func (tg *Group) wrap(t Tasker) Tasker {
+	for i := len(tg.middleware) - 1; i >= 0; i-- {
+		t = tg.middleware[i](t)
+	}
+	return t
+}
+
+// callOnPanic 调用 panic 回调，回调自身的 panic 被恢复并记录日志
+func (ex *execution) callOnPanic(fn func(int, interface{}, []byte), i int, recovered interface{}, stack []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			ex.logError("on panic callback error", &PanicError{Value: r}, map[string]interface{}{
+				"i": i,
+			})
+		}
+	}()
+	fn(i, recovered, stack)
+}
+
+// handleTimeout 调用任务的超时处理
+// 任务实现 TaskTimeoutCtx 时优先调用 TimeoutHandlerCtx，传入不随任务取消、带 WithHandlerTimeout 时限的上下文
+func (tg *Group) handleTimeout(ctx context.Context, t Tasker, ret Result) {
+	if t == nil {
+		return
+	}
+	if out, ok := taskAs[TaskTimeoutCtx](t); ok {
+		hctx, cancel := tg.handlerContext(ctx)
+		defer cancel()
+		out.TimeoutHandlerCtx(hctx, ret.Value, ret.Error)
+		return
+	}
+	if out, ok := taskAs[TaskTimeout](t); ok {
+		out.TimeoutHandler(ret.Value, ret.Error)
+	}
+}
+
+// handlerContext 为超时处理派生上下文：保留任务上下文的值但不随其取消，设置了 WithHandlerTimeout 时带时限
+func (tg *Group) handlerContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx = context.WithoutCancel(ctx)
+	if tg.handlerTimeout > 0 {
+		return context.WithTimeout(ctx, tg.handlerTimeout)
 	}
+	return ctx, func() {}
 }
@@ -1,11 +1,15 @@
 package job
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/goleak"
 	"runtime"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -162,9 +166,14 @@ func TestResultNoTimeout(t *testing.T) {
 	tg.AddTask(newTestSt("normal", 0, true))
 	ret, err := tg.Execute()
 	fmt.Println("ret: err", ret, err)
-	as.Error(err) // 有错误是对的
+	as.ErrorIs(err, ErrNoTimeoutForCollect) // 有错误是对的
+	as.ErrorContains(err, "timeout_no_result")
 	as.Nil(ret)
 
+	tg.Reset()
+	_, err = tg.Execute()
+	as.ErrorIs(err, ErrNoTasks)
+
 	time.Sleep(10 * time.Second)
 }
 
@@ -234,3 +243,1006 @@ func TestNums(t *testing.T) {
 
 	time.Sleep(10 * time.Second)
 }
+
+// execGoroutines 统计任务执行期间 ExecChan 额外占用的协程数（含任务协程）
+func execGoroutines(n int) int {
+	base := runtime.NumGoroutine()
+	release := make(chan struct{})
+	started := make(chan struct{}, n)
+	tg := NewTaskGroup("bench_goroutines", WithCollectRet(), WithDuration(time.Second))
+	for i := 0; i < n; i++ {
+		tg.AddTaskFunc(func() (interface{}, error) {
+			started <- struct{}{}
+			<-release
+			return nil, nil
+		})
+	}
+	ch := tg.ExecChan()
+	for i := 0; i < n; i++ {
+		<-started
+	}
+	count := runtime.NumGoroutine() - base
+	close(release)
+	<-ch
+	return count
+}
+
+func BenchmarkExecChanSingle(b *testing.B) {
+	b.ReportAllocs()
+	b.ReportMetric(float64(execGoroutines(1)), "goroutines/op")
+	for i := 0; i < b.N; i++ {
+		tg := NewTaskGroup("bench_single", WithCollectRet(), WithDuration(time.Second))
+		tg.AddTaskFunc(func() (interface{}, error) { return i, nil })
+		_, _ = tg.Execute()
+	}
+}
+
+func BenchmarkExecChanMulti(b *testing.B) {
+	b.ReportAllocs()
+	b.ReportMetric(float64(execGoroutines(2)), "goroutines/op")
+	for i := 0; i < b.N; i++ {
+		tg := NewTaskGroup("bench_multi", WithCollectRet(), WithDuration(time.Second))
+		tg.AddTaskFunc(func() (interface{}, error) { return i, nil })
+		tg.AddTaskFunc(func() (interface{}, error) { return i, nil })
+		_, _ = tg.Execute()
+	}
+}
+
+// TestSingleTask 单任务快速路径与普通路径结果一致
+func TestSingleTask(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("single", WithCollectRet(), WithDuration(100*time.Millisecond))
+	tg.AddTask(newTestSt("normal", 0, true))
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Equal([]Result{{Value: "normal"}}, ret)
+
+	tg.Reset()
+	tg.AddTask(newTestSt("timeout", 200*time.Millisecond, true))
+	ret, err = tg.Execute()
+	as.NoError(err)
+	as.Len(ret, 0)
+
+	time.Sleep(200 * time.Millisecond)
+}
+
+func TestAddChaining(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("chain", WithCollectRet(), WithDuration(100*time.Millisecond))
+	as.Same(tg, tg.Add(newTestSt("task", 0, true)))
+	ret, err := tg.AddFn(func() (interface{}, error) { return "fn", nil }).Execute()
+	as.NoError(err)
+	as.ElementsMatch([]Result{{Value: "task"}, {Value: "fn"}}, ret)
+}
+
+func TestContext(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("context", WithCollectRet(), WithDuration(time.Second))
+	as.Equal(context.Background(), tg.Context())
+
+	type key struct{}
+	parent := context.WithValue(context.Background(), key{}, "v")
+	tg.WithContext(parent)
+	as.Equal(parent, tg.Context())
+
+	inside := make(chan context.Context, 1)
+	tg.AddTaskFunc(func() (interface{}, error) {
+		inside <- tg.Context()
+		return nil, nil
+	})
+	_, err := tg.Execute()
+	as.NoError(err)
+	ctx := <-inside
+	as.Equal("v", ctx.Value(key{}))
+	_, ok := ctx.Deadline()
+	as.True(ok)
+	as.Equal(parent, tg.Context()) // 执行结束后回到设置的上下文
+}
+
+func TestNegativeDuration(t *testing.T) {
+	as := assert.New(t)
+
+	var executed int32
+	tg := NewTaskGroup("negative", WithDuration(-time.Second), WithCollectRet())
+	tg.AddTaskFunc(func() (interface{}, error) {
+		atomic.AddInt32(&executed, 1)
+		return nil, nil
+	})
+	_, err := tg.Execute()
+	as.ErrorIs(err, ErrNegativeDuration)
+	as.Contains(err.Error(), `"negative"`)
+
+	// 不收集结果时同样拒绝，而不是静默地异步执行
+	async := NewTaskGroup("negative_async", WithDuration(-time.Second))
+	async.AddTaskFunc(func() (interface{}, error) {
+		atomic.AddInt32(&executed, 1)
+		return nil, nil
+	})
+	as.ErrorIs(async.ExecuteAsync(), ErrNegativeDuration)
+	_, err = async.ExecuteUntil(func([]Result) bool { return true })
+	as.ErrorIs(err, ErrNegativeDuration)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, complete, err := async.ExecuteDeadline(ctx)
+	as.ErrorIs(err, ErrNegativeDuration)
+	as.False(complete)
+	as.Zero(atomic.LoadInt32(&executed))
+
+	// ExecuteDeadline 同样检查任务依赖和截止时间
+	deps := NewTaskGroup("deadline_deps")
+	deps.AddDependentTask("a", []string{"missing"}, func(map[string]Result) Tasker { return nil })
+	_, _, err = deps.ExecuteDeadline(ctx)
+	as.ErrorContains(err, "unknown task")
+	_, _, err = deps.ExecuteDeadline(context.Background())
+	as.Error(err)
+	nodeadline := NewTaskGroup("no_deadline")
+	nodeadline.AddTask(newTestSt("normal", 0, true))
+	_, _, err = nodeadline.ExecuteDeadline(context.Background())
+	as.ErrorIs(err, ErrNoDeadline)
+	as.Contains(err.Error(), `"no_deadline"`)
+}
+
+func TestInspectTasks(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("inspect")
+	tg.AddTask(newTestSt("st", 0, true))
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, nil })
+
+	caps := tg.InspectTasks()
+	as.Equal([]TaskCapabilities{
+		{Index: 0, Type: "*job.test_st", Timeout: true},
+		{Index: 1, Type: "job.TaskFunc", Timeout: false},
+	}, caps)
+}
+
+func TestInspectNamedTasks(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("inspect_named")
+	tg.AddNamedTask("user", newTestSt("st", 0, true))
+	tg.AddDependentTask("greet", []string{"user"}, func(map[string]Result) Tasker { return nil })
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, nil })
+
+	caps := tg.InspectTasks()
+	as.Len(caps, 3)
+	as.True(caps[0].Named)
+	as.Equal("user", caps[0].Name)
+	as.False(caps[0].Dependent)
+	as.True(caps[1].Named)
+	as.Equal("greet", caps[1].Name)
+	as.True(caps[1].Dependent)
+	as.False(caps[2].Named)
+	as.Empty(caps[2].Name)
+}
+
+// TestExecChanSingleSend 通道只发送一次最终结果后关闭，错误写在同一个结构体中
+func TestExecChanSingleSend(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("single_send", WithCollectRet())
+	tg.AddTask(newTestSt("normal", 0, true))
+	ch := tg.ExecChan()
+	grs, ok := <-ch
+	as.True(ok)
+	as.Error(grs.Error)
+	_, ok = <-ch
+	as.False(ok)
+
+	tg = NewTaskGroup("single_send", WithCollectRet(), WithDuration(100*time.Millisecond))
+	tg.AddTask(newTestSt("normal", 0, true))
+	ch = tg.ExecChan()
+	grs, ok = <-ch
+	as.True(ok)
+	as.NoError(grs.Error)
+	as.Len(grs.Results, 1)
+	_, ok = <-ch
+	as.False(ok)
+}
+
+// memLog 记录日志，便于断言
+type memLog struct {
+	mu      sync.Mutex
+	infos   []string
+	errs    []string
+	infoDat []map[string]interface{}
+	errDat  []map[string]interface{}
+}
+
+func (l *memLog) Info(message string, data map[string]interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.infos = append(l.infos, message)
+	l.infoDat = append(l.infoDat, data)
+}
+
+func (l *memLog) Error(message string, err error, data map[string]interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.errs = append(l.errs, message)
+	l.errDat = append(l.errDat, data)
+}
+
+func (l *memLog) infoCount(message string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := 0
+	for _, m := range l.infos {
+		if m == message {
+			n++
+		}
+	}
+	return n
+}
+
+// infoData 返回最后一条 message 日志的数据
+func (l *memLog) infoData(message string) map[string]interface{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := len(l.infos) - 1; i >= 0; i-- {
+		if l.infos[i] == message {
+			return l.infoDat[i]
+		}
+	}
+	return nil
+}
+
+func (l *memLog) errCount(message string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := 0
+	for _, m := range l.errs {
+		if m == message {
+			n++
+		}
+	}
+	return n
+}
+
+func TestHeartbeat(t *testing.T) {
+	as := assert.New(t)
+
+	log := &memLog{}
+	tg := NewTaskGroup("heartbeat", WithDuration(time.Second), WithHeartbeat(20*time.Millisecond), WithLog(log))
+	tg.AddTask(newTestSt("normal", 0, true))
+	tg.AddTask(newTestSt("slow", 110*time.Millisecond, true))
+	_, err := tg.Execute()
+	as.NoError(err)
+
+	beats := log.infoCount("task group still running")
+	as.GreaterOrEqual(beats, 3)
+
+	// 执行结束后定时器已停止
+	time.Sleep(60 * time.Millisecond)
+	as.Equal(beats, log.infoCount("task group still running"))
+}
+
+func BenchmarkExecChanFanOut(b *testing.B) {
+	b.ReportAllocs()
+	b.ReportMetric(float64(execGoroutines(100)-100), "helpers/op")
+	for i := 0; i < b.N; i++ {
+		tg := NewTaskGroup("bench_fan_out", WithCollectRet(), WithDuration(time.Second))
+		for j := 0; j < 100; j++ {
+			tg.AddTaskFunc(func() (interface{}, error) { return j, nil })
+		}
+		_, _ = tg.Execute()
+	}
+}
+
+// BenchmarkReset 对比 Reset 与 ResetKeep 在重复添加任务时的分配
+func BenchmarkReset(b *testing.B) {
+	task := newTestSt("normal", 0, true)
+	for _, keep := range []bool{false, true} {
+		name := "Reset"
+		if keep {
+			name = "ResetKeep"
+		}
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			tg := NewTaskGroup("bench_reset")
+			for i := 0; i < b.N; i++ {
+				if keep {
+					tg.ResetKeep()
+				} else {
+					tg.Reset()
+				}
+				for j := 0; j < 100; j++ {
+					tg.AddTask(task)
+				}
+			}
+		})
+	}
+}
+
+func TestResetKeep(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("reset_keep", WithCollectRet(), WithDuration(time.Second))
+	tg.AddNamedTask("a", newTestSt("a", 0, true))
+	tg.AddTask(newTestSt("b", 0, true))
+	tg.ResetKeep()
+	as.Empty(tg.tasks)
+	as.Empty(tg.names)
+	as.Equal(2, cap(tg.tasks))
+
+	tg.AddNamedTask("a", newTestSt("normal", 0, true))
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Equal([]Result{{Value: "normal"}}, ret)
+}
+
+type deadlineSt struct {
+	*test_st
+	deadline time.Time
+	timedOut chan interface{}
+}
+
+func (s *deadlineSt) Deadline() time.Time {
+	return s.deadline
+}
+
+func (s *deadlineSt) TimeoutHandler(ret interface{}, err error) {
+	s.timedOut <- ret
+}
+
+// TestDeadlineTasker 任务自带截止时间，早于组截止时间时组提前返回
+func TestDeadlineTasker(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("deadline", WithCollectRet(), WithDuration(time.Second))
+	tg.AddTask(newTestSt("normal", 0, true))
+	task := &deadlineSt{
+		test_st:  newTestSt("sla", 200*time.Millisecond, true),
+		deadline: time.Now().Add(50 * time.Millisecond),
+		timedOut: make(chan interface{}, 1),
+	}
+	tg.AddTask(task)
+
+	start := time.Now()
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Less(time.Since(start), 150*time.Millisecond)
+	as.Equal([]Result{{Value: "normal"}}, ret)
+	as.Equal("sla", <-task.timedOut)
+
+	caps := tg.InspectTasks()
+	as.True(caps[1].Deadline)
+	as.False(caps[0].Deadline)
+}
+
+func TestExecuteDeadline(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("deadline_all_done")
+	tg.AddTask(newTestSt("normal", 0, true))
+	tg.AddTask(newTestSt("normal2", 10*time.Millisecond, true))
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	start := time.Now()
+	ret, complete, err := tg.ExecuteDeadline(ctx)
+	cancel()
+	as.NoError(err)
+	as.True(complete)
+	as.Len(ret, 2)
+	as.Less(time.Since(start), 200*time.Millisecond)
+
+	// 部分完成
+	tg = NewTaskGroup("deadline_partial")
+	tg.AddTask(newTestSt("normal", 0, true))
+	tg.AddTask(newTestSt("timeout", 200*time.Millisecond, true))
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	start = time.Now()
+	ret, complete, err = tg.ExecuteDeadline(ctx)
+	cancel()
+	as.NoError(err)
+	as.False(complete)
+	as.Equal([]Result{{Value: "normal"}}, ret)
+	as.Less(time.Since(start), 150*time.Millisecond)
+
+	// 全部未完成，组超时早于 ctx 截止
+	tg = NewTaskGroup("deadline_none", WithDuration(50*time.Millisecond))
+	tg.AddTask(newTestSt("timeout", 200*time.Millisecond, true))
+	tg.AddTask(newTestSt("timeout2", 200*time.Millisecond, true))
+	start = time.Now()
+	ret, complete, err = tg.ExecuteDeadline(context.Background())
+	as.NoError(err)
+	as.False(complete)
+	as.Len(ret, 0)
+	as.Less(time.Since(start), 150*time.Millisecond)
+
+	// 没有任何截止时间
+	tg = NewTaskGroup("deadline_missing")
+	tg.AddTask(newTestSt("normal", 0, true))
+	_, _, err = tg.ExecuteDeadline(context.Background())
+	as.Error(err)
+
+	time.Sleep(200 * time.Millisecond)
+}
+
+// TestResultChanSize 缓存小于任务数时依然能收集到全部结果
+func TestResultChanSize(t *testing.T) {
+	as := assert.New(t)
+
+	for _, size := range []int{0, 1, 3} {
+		tg := NewTaskGroup("ret_chan_size", WithCollectRet(), WithDuration(time.Second), WithResultChanSize(size))
+		for i := 0; i < 20; i++ {
+			tg.AddTaskFunc(func() (interface{}, error) { return i, nil })
+		}
+		ret, err := tg.Execute()
+		as.NoError(err)
+		as.Len(ret, 20)
+	}
+
+	// 超时后阻塞的生产者放弃发送
+	tg := NewTaskGroup("ret_chan_size_timeout", WithDuration(50*time.Millisecond), WithResultChanSize(0))
+	tg.AddTask(newTestSt("normal", 0, true))
+	tg.AddTask(newTestSt("timeout", 100*time.Millisecond, true))
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Nil(ret)
+
+	time.Sleep(100 * time.Millisecond)
+}
+
+// TestTaskContext 每个任务拥有独立的上下文，取消单个任务不影响其他任务
+func TestTaskContext(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("task_ctx", WithCollectRet(), WithDuration(time.Second))
+	started := make(chan struct{})
+	canceled := make(chan error, 1)
+	tg.AddTaskFuncCtx(func(ctx context.Context) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		canceled <- ctx.Err()
+		return nil, ctx.Err()
+	})
+	tg.AddTaskFuncCtx(func(ctx context.Context) (interface{}, error) {
+		time.Sleep(50 * time.Millisecond)
+		return "sibling", ctx.Err()
+	})
+
+	ch := tg.ExecChan()
+	<-started
+	as.True(tg.CancelTask(0))
+	as.False(tg.CancelTask(2))
+
+	grs := <-ch
+	as.NoError(grs.Error)
+	as.Equal([]Result{{Value: "sibling"}}, grs.Results)
+	as.Equal(context.Canceled, <-canceled)
+}
+
+// TestTaskContextAsync 异步模式下任务上下文在任务结束前保持有效
+func TestTaskContextAsync(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("task_ctx_async")
+	errs := make(chan error, 1)
+	tg.AddTaskFuncCtx(func(ctx context.Context) (interface{}, error) {
+		time.Sleep(10 * time.Millisecond)
+		errs <- ctx.Err()
+		return nil, nil
+	})
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Nil(ret)
+	as.NoError(<-errs)
+	time.Sleep(10 * time.Millisecond)
+}
+
+// handlerSt 记录 TimeoutHandler 调用
+type handlerSt struct {
+	err     error
+	handled chan error
+}
+
+func (s *handlerSt) Execute() (interface{}, error) {
+	return nil, s.err
+}
+
+func (s *handlerSt) TimeoutHandler(ret interface{}, err error) {
+	s.handled <- err
+}
+
+func TestErrorTriggersTimeoutHandler(t *testing.T) {
+	as := assert.New(t)
+
+	failed := errors.New("failed")
+	task := &handlerSt{err: failed, handled: make(chan error, 1)}
+	tg := NewTaskGroup("err_timeout", WithCollectRet(), WithDuration(time.Second), WithErrorTriggersTimeoutHandler())
+	tg.AddTask(task)
+	tg.AddTask(&handlerSt{handled: make(chan error, 1)})
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Len(ret, 2)
+	as.Equal(failed, <-task.handled)
+
+	// 默认不触发
+	task = &handlerSt{err: failed, handled: make(chan error, 1)}
+	tg = NewTaskGroup("err_no_timeout", WithCollectRet(), WithDuration(time.Second))
+	tg.AddTask(task)
+	_, err = tg.Execute()
+	as.NoError(err)
+	as.Len(task.handled, 0)
+}
+
+// TestExecChanAbandoned 不读取 ExecChan 返回的通道，任务结束后不残留协程
+func TestExecChanAbandoned(t *testing.T) {
+	opts := goleak.IgnoreCurrent()
+
+	tg := NewTaskGroup("abandoned", WithCollectRet(), WithDuration(50*time.Millisecond))
+	tg.AddTask(newTestSt("normal", 0, true))
+	tg.AddTask(newTestSt("timeout", 100*time.Millisecond, true))
+	_ = tg.ExecChan()
+
+	time.Sleep(150 * time.Millisecond)
+	goleak.VerifyNone(t, opts)
+}
+
+func TestName(t *testing.T) {
+	as := assert.New(t)
+
+	log := &memLog{}
+	tg := NewTaskGroup("old", WithDuration(time.Second), WithLog(log))
+	as.Equal("old", tg.Name())
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	tg.AddTaskFunc(func() (interface{}, error) {
+		close(started)
+		<-release
+		panic("boom")
+	})
+	ch := tg.ExecChan()
+	<-started
+	tg.SetName("new")
+	close(release)
+	<-ch
+
+	as.Equal("new", tg.Name())
+	as.Equal("old", log.errDat[0]["name"]) // 执行中改名不影响该次执行的日志
+}
+
+// timeoutSt 记录 TimeoutHandler 收到的结果
+type timeoutSt struct {
+	*test_st
+	timedOut chan interface{}
+}
+
+func newTimeoutSt(name string, duration time.Duration) *timeoutSt {
+	return &timeoutSt{
+		test_st:  newTestSt(name, duration, true),
+		timedOut: make(chan interface{}, 1),
+	}
+}
+
+func (s *timeoutSt) TimeoutHandler(ret interface{}, err error) {
+	s.timedOut <- ret
+}
+
+func TestExecChanWithCancel(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("chan_cancel", WithCollectRet(), WithDuration(time.Second))
+	task := newTimeoutSt("slow", 100*time.Millisecond)
+	tg.AddTask(newTestSt("normal", 0, true))
+	tg.AddTask(task)
+
+	ch, cancel := tg.ExecChanWithCancel()
+	time.Sleep(20 * time.Millisecond)
+	start := time.Now()
+	cancel()
+	grs := <-ch
+	as.Less(time.Since(start), 50*time.Millisecond)
+	as.NoError(grs.Error)
+	as.Equal([]Result{{Value: "normal"}}, grs.Results)
+	as.Equal("slow", <-task.timedOut) // 被取消的任务走超时处理
+	cancel()
+
+	tg.Reset()
+	_, cancel = tg.ExecChanWithCancel()
+	cancel()
+}
+
+func TestLogSampling(t *testing.T) {
+	as := assert.New(t)
+
+	log := &memLog{}
+	tg := NewTaskGroup("log_sampling", WithDuration(time.Second), WithLog(log), WithLogSampling(0.1))
+	for i := 0; i < 50; i++ {
+		tg.AddTaskFunc(func() (interface{}, error) { panic(i) })
+	}
+	_, err := tg.Execute()
+	as.NoError(err)
+
+	as.Equal(5, log.errCount("task run error"))
+	as.Equal(1, log.infoCount("task errors sampled"))
+	as.Equal(int64(45), log.infoDat[0]["suppressed"])
+	as.Equal(int64(50), log.infoDat[0]["panics"])
+}
+
+// TestSinkFlushOnCancel 取消时已完成的结果仍交给 sink
+func TestSinkFlushOnCancel(t *testing.T) {
+	as := assert.New(t)
+
+	var sunk []Result
+	tg := NewTaskGroup("sink", WithDuration(time.Second), WithSink(func(r Result) {
+		time.Sleep(30 * time.Millisecond) // 慢 sink，取消时后续结果仍在缓存中
+		sunk = append(sunk, r)
+	}))
+	tg.AddTask(newTestSt("normal", 0, true))
+	tg.AddTask(newTestSt("normal2", 0, true))
+	tg.AddTask(newTestSt("slow", 100*time.Millisecond, true))
+
+	ch, cancel := tg.ExecChanWithCancel()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	grs := <-ch
+	as.NoError(grs.Error)
+	as.Nil(grs.Results)
+	as.ElementsMatch([]Result{{Value: "normal"}, {Value: "normal2"}}, sunk)
+
+	time.Sleep(100 * time.Millisecond)
+}
+
+func TestOnPanic(t *testing.T) {
+	as := assert.New(t)
+
+	log := &memLog{}
+	var mu sync.Mutex
+	recovered := make(map[int]interface{})
+	tg := NewTaskGroup("on_panic", WithDuration(time.Second), WithLog(log),
+		WithOnPanic(func(index int, r interface{}, stack []byte) {
+			mu.Lock()
+			recovered[index] = r
+			mu.Unlock()
+			as.NotEmpty(stack)
+			if index == 1 {
+				panic("callback")
+			}
+		}))
+	tg.AddTaskFunc(func() (interface{}, error) { panic("boom") })
+	tg.AddTaskFunc(func() (interface{}, error) { panic(42) })
+	tg.AddTask(newTestSt("normal", 0, true))
+	_, err := tg.Execute()
+	as.NoError(err)
+
+	as.Equal(map[int]interface{}{0: "boom", 1: 42}, recovered)
+	as.Equal(2, log.errCount("task run error"))
+	as.Equal(1, log.errCount("on panic callback error"))
+}
+
+func TestNewTaskGroupWithDefaults(t *testing.T) {
+	as := assert.New(t)
+
+	log := &memLog{}
+	defaults := OptionSet{WithDuration(time.Second), WithCollectRet(), WithLog(log)}
+
+	tg := NewTaskGroupWithDefaults("defaults", defaults)
+	as.Equal(time.Second, tg.timeout)
+	as.True(tg.collectResult)
+	as.Equal(log, tg.log)
+
+	// 覆盖项在默认值之后绑定
+	tg = NewTaskGroupWithDefaults("overrides", defaults, WithDuration(time.Minute))
+	as.Equal(time.Minute, tg.timeout)
+	as.True(tg.collectResult)
+
+	// OptionSet 也可作为普通 Option 使用
+	tg = NewTaskGroup("set", defaults, WithDuration(time.Millisecond))
+	as.Equal(time.Millisecond, tg.timeout)
+}
+
+func TestLogContextExtractor(t *testing.T) {
+	as := assert.New(t)
+
+	type traceKey struct{}
+	log := &memLog{}
+	calls := 0
+	tg := NewTaskGroup("log_ctx", WithDuration(time.Second), WithLog(log),
+		WithCtx(context.WithValue(context.Background(), traceKey{}, "trace-1")),
+		WithLogContextExtractor(func(ctx context.Context) map[string]interface{} {
+			calls++
+			return map[string]interface{}{"trace_id": ctx.Value(traceKey{}), "i": -1}
+		}))
+	tg.AddTaskFunc(func() (interface{}, error) { panic("boom") })
+	tg.AddTaskFunc(func() (interface{}, error) { panic("boom") })
+	_, err := tg.Execute()
+	as.NoError(err)
+
+	as.Equal(1, calls)
+	as.Equal(2, log.errCount("task run error"))
+	for _, data := range log.errDat {
+		as.Equal("trace-1", data["trace_id"])
+		as.Equal("log_ctx", data["name"])
+		as.NotEqual(-1, data["i"]) // 已有字段优先
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	as := assert.New(t)
+
+	var mu sync.Mutex
+	var order []string
+	trace := func(name string) func(Tasker) Tasker {
+		return func(next Tasker) Tasker {
+			return TaskFunc(func() (interface{}, error) {
+				mu.Lock()
+				order = append(order, name)
+				mu.Unlock()
+				return next.Execute()
+			})
+		}
+	}
+	tg := NewTaskGroup("middleware", WithCollectRet(), WithDuration(time.Second),
+		WithMiddleware(trace("outer")), WithMiddleware(trace("inner")))
+	tg.AddTask(newTestSt("normal", 0, true))
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Equal([]Result{{Value: "normal"}}, ret)
+	as.Equal([]string{"outer", "inner"}, order)
+
+	// 中间件的 panic 被恢复
+	log := &memLog{}
+	tg = NewTaskGroup("middleware_panic", WithCollectRet(), WithDuration(time.Second), WithLog(log),
+		WithMiddleware(func(next Tasker) Tasker { panic("middleware") }))
+	tg.AddTask(newTestSt("normal", 0, true))
+	ret, err = tg.Execute()
+	as.NoError(err)
+	as.Empty(ret)
+	as.Equal(1, log.errCount("task run error"))
+}
+
+func TestOrderedSink(t *testing.T) {
+	as := assert.New(t)
+
+	var indices []int
+	var values []interface{}
+	tg := NewTaskGroup("ordered_sink", WithDuration(100*time.Millisecond), WithOrderedSink(func(i int, r Result) {
+		indices = append(indices, i)
+		values = append(values, r.Value)
+	}))
+	tg.AddTask(newTestSt("slow", 30*time.Millisecond, true))
+	tg.AddTask(newTestSt("normal", 0, true))
+	tg.AddTask(newTestSt("timeout", 200*time.Millisecond, true))
+	tg.AddTask(newTestSt("normal2", 0, true))
+	_, err := tg.Execute()
+	as.NoError(err)
+
+	// 超时任务之后的结果在收集结束时按顺序补齐
+	as.Equal([]int{0, 1, 3}, indices)
+	as.Equal([]interface{}{"slow", "normal", "normal2"}, values)
+
+	time.Sleep(200 * time.Millisecond)
+}
+
+func TestExecuteUntil(t *testing.T) {
+	as := assert.New(t)
+
+	slow := newTimeoutSt("slow", 100*time.Millisecond)
+	tg := NewTaskGroup("until")
+	tg.AddTask(newTestSt("normal", 0, true))
+	tg.AddTask(newTestSt("normal2", 10*time.Millisecond, true))
+	tg.AddTask(slow)
+
+	start := time.Now()
+	ret, err := tg.ExecuteUntil(func(results []Result) bool { return len(results) == 2 })
+	as.NoError(err)
+	as.Equal([]Result{{Value: "normal"}, {Value: "normal2"}}, ret)
+	as.Less(time.Since(start), 100*time.Millisecond)
+	as.Equal("slow", <-slow.timedOut) // 被取消的任务走超时处理
+
+	// 条件一直不满足时等待所有任务结束
+	tg.Reset()
+	tg.AddTask(newTestSt("normal", 0, true))
+	ret, err = tg.ExecuteUntil(func([]Result) bool { return false })
+	as.NoError(err)
+	as.Equal([]Result{{Value: "normal"}}, ret)
+}
+
+func TestRunning(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("running", WithDuration(time.Second))
+	as.Nil(tg.Running())
+	tg.AddTask(newTestSt("normal", 0, true))
+	tg.AddTask(newTestSt("slow", 50*time.Millisecond, true))
+	tg.AddTask(newTestSt("slow2", 50*time.Millisecond, true))
+
+	ch := tg.ExecChan()
+	time.Sleep(20 * time.Millisecond)
+	as.Equal([]int{1, 2}, tg.Running())
+	<-ch
+	as.Empty(tg.Running())
+}
+
+func TestEagerCancel(t *testing.T) {
+	as := assert.New(t)
+
+	// 慢 sink 推迟最终结果，返回组上下文取消比收到结果早多久
+	lead := func(opts ...Option) time.Duration {
+		opts = append(opts, WithDuration(time.Second), WithSink(func(Result) { time.Sleep(50 * time.Millisecond) }))
+		tg := NewTaskGroup("eager_cancel", opts...)
+		tg.AddTask(newTestSt("normal", 0, true))
+
+		ch := tg.ExecChan()
+		cancelled := make(chan time.Time, 1)
+		context.AfterFunc(tg.cur.ctx, func() { cancelled <- time.Now() })
+		grs := <-ch
+		received := time.Now()
+		as.NoError(grs.Error)
+		return received.Sub(<-cancelled)
+	}
+
+	as.GreaterOrEqual(lead(WithEagerCancel()), 40*time.Millisecond)
+	as.Less(lead(), 40*time.Millisecond) // 默认在发送最终结果后才取消
+}
+
+func TestExecuteWithCallback(t *testing.T) {
+	as := assert.New(t)
+
+	type call struct {
+		results []Result
+		err     error
+	}
+	calls := make(chan call, 2)
+	tg := NewTaskGroup("callback", WithCollectRet(), WithDuration(time.Second))
+	tg.AddTask(newTestSt("normal", 0, true))
+	tg.ExecuteWithCallback(func(results []Result, err error) { calls <- call{results, err} })
+	c := <-calls
+	as.NoError(c.err)
+	as.Equal([]Result{{Value: "normal"}}, c.results)
+
+	// 配置错误时同样回调一次
+	tg.Reset()
+	tg.ExecuteWithCallback(func(results []Result, err error) { calls <- call{results, err} })
+	c = <-calls
+	as.ErrorIs(c.err, ErrNoTasks)
+
+	// 回调的 panic 被恢复
+	log := &memLog{}
+	tg = NewTaskGroup("callback_panic", WithDuration(time.Second), WithLog(log))
+	tg.AddTask(newTestSt("normal", 0, true))
+	done := make(chan struct{})
+	tg.ExecuteWithCallback(func([]Result, error) {
+		defer close(done)
+		panic("boom")
+	})
+	<-done
+	time.Sleep(10 * time.Millisecond)
+	as.Equal(1, log.errCount("execute callback error"))
+	as.Empty(calls)
+}
+
+func TestAggregator(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("aggregator", WithDuration(time.Second),
+		WithAggregator(func(acc interface{}, r Result) interface{} {
+			if r.Error != nil {
+				return acc
+			}
+			return acc.(int) + r.Value.(int)
+		}, 0))
+	for i := 1; i <= 4; i++ {
+		tg.AddTaskFunc(func() (interface{}, error) { return i, nil })
+	}
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, errors.New("failed") })
+
+	grs := <-tg.ExecChan()
+	as.NoError(grs.Error)
+	as.Nil(grs.Results) // 未收集结果
+	as.Equal(10, grs.Aggregate)
+}
+
+// ctxHandlerSt 超时处理带上下文的任务
+type ctxHandlerSt struct {
+	*test_st
+	handled chan error
+}
+
+func (s *ctxHandlerSt) TimeoutHandler(ret interface{}, err error) {
+	panic("TimeoutHandlerCtx takes precedence")
+}
+
+// TimeoutHandlerCtx 模拟一直等待上下文结束的清理
+func (s *ctxHandlerSt) TimeoutHandlerCtx(ctx context.Context, ret interface{}, err error) {
+	if ctx.Err() != nil {
+		s.handled <- errors.New("handler context already done")
+		return
+	}
+	<-ctx.Done()
+	s.handled <- ctx.Err()
+}
+
+func TestTimeoutHandlerCtx(t *testing.T) {
+	as := assert.New(t)
+
+	task := &ctxHandlerSt{test_st: newTestSt("slow", 50*time.Millisecond, true), handled: make(chan error, 1)}
+	tg := NewTaskGroup("handler_ctx", WithDuration(10*time.Millisecond), WithHandlerTimeout(20*time.Millisecond))
+	tg.AddTask(task)
+	_, err := tg.Execute()
+	as.NoError(err)
+
+	as.ErrorIs(<-task.handled, context.DeadlineExceeded) // 清理被 WithHandlerTimeout 限时
+	as.True(tg.InspectTasks()[0].Timeout)
+}
+
+func TestRunID(t *testing.T) {
+	as := assert.New(t)
+
+	log := &memLog{}
+	var mu sync.Mutex
+	var metricIDs []string
+	tg := NewTaskGroup("run_id", WithDuration(time.Second), WithLog(log), WithRunID(""),
+		WithMetricsHook(func(m TaskMetric) {
+			mu.Lock()
+			metricIDs = append(metricIDs, m.RunID)
+			mu.Unlock()
+		}))
+	tg.AddTaskFunc(func() (interface{}, error) { panic("boom") })
+
+	first := <-tg.ExecChan()
+	as.NotEmpty(first.RunID)
+	as.Equal(1, log.errCount("task run error"))
+	as.Equal(first.RunID, log.errDat[0]["run_id"])
+
+	second := <-tg.ExecChan()
+	as.NotEmpty(second.RunID)
+	as.NotEqual(first.RunID, second.RunID) // 自动生成的 ID 每次执行都不同
+	as.Eventually(func() bool { // 指标回调在结果发送之后执行
+		mu.Lock()
+		defer mu.Unlock()
+		return len(metricIDs) == 2
+	}, time.Second, time.Millisecond)
+	mu.Lock()
+	as.ElementsMatch([]string{first.RunID, second.RunID}, metricIDs)
+	mu.Unlock()
+
+	fixed := NewTaskGroup("run_id_fixed", WithDuration(time.Second), WithRunID("req-42"))
+	fixed.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+	as.Equal("req-42", (<-fixed.ExecChan()).RunID)
+	as.Equal("req-42", (<-fixed.ExecChan()).RunID)
+
+	plain := NewTaskGroup("run_id_off", WithDuration(time.Second))
+	plain.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+	as.Empty((<-plain.ExecChan()).RunID)
+}
+
+func TestSnapshotResults(t *testing.T) {
+	as := assert.New(t)
+
+	release := make(chan struct{})
+	tg := NewTaskGroup("snapshot", WithDuration(time.Second), WithCollectRet())
+	as.Nil(tg.SnapshotResults())
+	tg.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+	tg.AddTaskFunc(func() (interface{}, error) {
+		<-release
+		return 2, nil
+	})
+
+	done := make(chan []Result, 1)
+	go func() {
+		results, _ := tg.Execute()
+		done <- results
+	}()
+
+	as.Eventually(func() bool { return len(tg.SnapshotResults()) == 1 }, time.Second, time.Millisecond)
+	snapshot := tg.SnapshotResults()
+	as.Equal([]Result{{Value: 1}}, snapshot)
+	snapshot[0].Value = "changed" // 返回的是副本
+
+	close(release)
+	results := <-done
+	as.Len(results, 2)
+	as.Equal(results, tg.SnapshotResults())
+
+	plain := NewTaskGroup("snapshot_plain", WithDuration(time.Second))
+	plain.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+	_, err := plain.Execute()
+	as.NoError(err)
+	as.Nil(plain.SnapshotResults()) // 不收集结果时为 nil
+}
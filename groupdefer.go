@@ -0,0 +1,74 @@
+package job
+
+import (
+	"bytes"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// WithDefer 注册任务组级的 defer：每次执行在所有任务结束且收集结束后调用 fn 一次，
+// 适合释放执行前为整组获取的共享资源；可多次设置，按注册的相反顺序（LIFO）调用
+// 配置错误、没有任务等未启动任务就返回的执行同样会调用，此时在返回前、持有任务组的锁时调用，fn 中不要调用该任务组的方法；
+// 收集方（如 WithSink）panic 时仍会在所有任务结束后调用；fn 的 panic 被恢复并记录日志，不影响其余 defer
+// 注意收集可能因超时先于任务结束，因此 Execute 返回时 defer 不一定已经调用
+func WithDefer(fn func()) Option {
+	return deferOption(fn)
+}
+
+type deferOption func()
+
+func (d deferOption) bind(o *options) {
+	o.Defers = append(o.Defers, d)
+}
+
+// hasCollector 返回本次执行是否有收集方，没有收集方的执行（异步执行、RunInErrGroup）只等待任务结束
+func (ex *execution) hasCollector() bool {
+	return !ex.async && ex.errGroup == nil
+}
+
+// armDefers 在启动任务前确定 defer 需要等待的结束事件：所有任务结束，以及收集结束（有收集方时）
+func (ex *execution) armDefers(defers []func()) {
+	if len(defers) == 0 {
+		return
+	}
+	ex.defers = defers
+	ex.deferLeft = 1
+	if ex.hasCollector() {
+		ex.deferLeft = 2
+	}
+}
+
+// deferDone 记录一个结束事件，最后一个事件调用 defer
+func (ex *execution) deferDone() {
+	if ex.defers == nil || atomic.AddInt32(&ex.deferLeft, -1) != 0 {
+		return
+	}
+	runDefers(ex.defers, func(err error) {
+		ex.logError("group defer error", err, map[string]interface{}{})
+	})
+}
+
+// runEarlyDefers 未启动任务就返回时调用 defer，调用方需持有 tg.mu
+func (tg *Group) runEarlyDefers() {
+	runDefers(tg.defers, func(err error) {
+		logError(tg.log, "group defer error", err, map[string]interface{}{"name": tg.name})
+	})
+}
+
+// runDefers 按相反顺序调用 defers，恢复每个 defer 的 panic 并交给 onPanic
+func runDefers(defers []func(), onPanic func(error)) {
+	for i := len(defers) - 1; i >= 0; i-- {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					stack := debug.Stack()
+					if line := bytes.IndexByte(stack, '\n'); line >= 0 {
+						stack = stack[line+1:]
+					}
+					onPanic(&PanicError{Value: r, Stack: stack})
+				}
+			}()
+			defers[i]()
+		}()
+	}
+}
@@ -0,0 +1,69 @@
+package job
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithDefer(t *testing.T) {
+	as := assert.New(t)
+
+	var mu sync.Mutex
+	var order []string
+	record := func(s string) func() {
+		return func() {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, s)
+		}
+	}
+	recorded := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), order...)
+	}
+
+	log := &memLog{}
+	release := make(chan struct{})
+	tg := NewTaskGroup("defer", WithDuration(20*time.Millisecond), WithCollectRet(), WithLog(log),
+		WithDefer(record("first")), WithDefer(func() { panic("boom") }), WithDefer(record("last")))
+	tg.AddTaskFunc(func() (interface{}, error) {
+		<-release
+		record("task")()
+		return nil, nil
+	})
+
+	_, err := tg.Execute()
+	as.NoError(err)
+	as.Empty(recorded()) // 收集已因超时结束，任务仍在执行
+	close(release)
+	as.Eventually(func() bool { return len(recorded()) == 3 }, time.Second, 5*time.Millisecond)
+	as.Equal([]string{"task", "last", "first"}, recorded())
+	as.Equal(1, log.errCount("group defer error"))
+
+	// 配置错误时同样调用
+	order = nil
+	tg = NewTaskGroup("defer_config_error", WithCollectRet(), WithDefer(record("config")))
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, nil })
+	_, err = tg.Execute()
+	as.ErrorIs(err, ErrNoTimeoutForCollect)
+	as.Equal([]string{"config"}, recorded())
+}
+
+func TestWithDeferAsync(t *testing.T) {
+	as := assert.New(t)
+
+	done := make(chan struct{})
+	tg := NewTaskGroup("defer_async", WithDefer(func() { close(done) }))
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, nil })
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, nil })
+	as.NoError(tg.ExecuteAsync())
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		as.Fail("defer not called")
+	}
+}
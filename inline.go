@@ -0,0 +1,51 @@
+package job
+
+import "context"
+
+// taskSet 任务列表及按任务下标记录的属性
+type taskSet struct {
+	tasks     []Tasker
+	names     map[int]string
+	fallbacks map[int]interface{}
+	taskCtxs  map[int]context.Context
+	cleanups  map[int]func()
+	stages    map[int]int
+}
+
+// swapTasks 替换任务列表及按下标记录的任务属性，返回原来的值，调用方需持有 tg.mu
+func (tg *Group) swapTasks(s taskSet) taskSet {
+	old := taskSet{
+		tasks:     tg.tasks,
+		names:     tg.names,
+		fallbacks: tg.fallbacks,
+		taskCtxs:  tg.taskCtxs,
+		cleanups:  tg.cleanups,
+		stages:    tg.stages,
+	}
+	tg.tasks, tg.names, tg.fallbacks, tg.taskCtxs, tg.cleanups = s.tasks, s.names, s.fallbacks, s.taskCtxs, s.cleanups
+	tg.stages = s.stages
+	return old
+}
+
+// ExecuteTasks 以组的配置执行一组临时任务，不修改通过 AddTask 添加的任务，结果语义与 Execute 相同
+// 可以并发、重复调用，适合把配置好的任务组当作模板发起临时的并发调用；
+// 执行期间 Running、CancelTask 针对的是这次执行
+func (tg *Group) ExecuteTasks(tasks ...Tasker) ([]Result, error) {
+	tg.mu.Lock()
+	saved := tg.swapTasks(taskSet{tasks: tasks})
+	ch, _ := tg.execChan()
+	tg.swapTasks(saved)
+	tg.mu.Unlock()
+
+	grs := <-ch
+	return grs.Results, grs.Error
+}
+
+// ExecuteFuncs 与 ExecuteTasks 相同，任务以函数形式给出
+func (tg *Group) ExecuteFuncs(fns ...TaskFunc) ([]Result, error) {
+	tasks := make([]Tasker, len(fns))
+	for i, fn := range fns {
+		tasks[i] = fn
+	}
+	return tg.ExecuteTasks(tasks...)
+}
@@ -0,0 +1,39 @@
+package job
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteTasks(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("inline", WithCollectRet(), WithDuration(time.Second))
+	tg.AddTask(newTestSt("template", 0, true))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ret, err := tg.ExecuteFuncs(
+				func() (interface{}, error) { return i, nil },
+				func() (interface{}, error) { return i, nil },
+			)
+			as.NoError(err)
+			as.Equal([]Result{{Value: i}, {Value: i}}, ret)
+		}()
+	}
+	wg.Wait()
+
+	// 不影响已添加的任务
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Equal([]Result{{Value: "template"}}, ret)
+
+	_, err = tg.ExecuteTasks()
+	as.ErrorIs(err, ErrNoTasks)
+}
@@ -0,0 +1,45 @@
+package job
+
+import "fmt"
+
+// TaskCapabilities 描述单个任务实现了哪些可选接口
+type TaskCapabilities struct {
+	Index     int    // 任务在组内的下标
+	Type      string // 任务的具体类型
+	Timeout   bool   // 实现了 TaskTimeout 或 TaskTimeoutCtx
+	Deadline  bool   // 实现了 DeadlineTasker
+	Context   bool   // 实现了 ContextTasker
+	Named     bool   // 通过 AddNamedTask 或 AddDependentTask 命名
+	Name      string // 任务名称，未命名时为空
+	Dependent bool   // 通过 AddDependentTask 添加的依赖任务
+}
+
+// InspectTasks 报告组内每个任务实现的可选接口，用于开发期排查任务接线错误
+// 只读，不会执行任务
+func (tg *Group) InspectTasks() []TaskCapabilities {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+
+	caps := make([]TaskCapabilities, 0, len(tg.tasks))
+	for i, t := range tg.tasks {
+		_, timeout := taskAs[TaskTimeout](t)
+		if _, ok := taskAs[TaskTimeoutCtx](t); ok {
+			timeout = true
+		}
+		_, deadline := taskAs[DeadlineTasker](t)
+		_, ctx := taskAs[ContextTasker](t)
+		name, named := tg.names[i]
+		_, dependent := t.(*dependentTask)
+		caps = append(caps, TaskCapabilities{
+			Index:     i,
+			Type:      fmt.Sprintf("%T", t),
+			Timeout:   timeout,
+			Deadline:  deadline,
+			Context:   ctx,
+			Named:     named,
+			Name:      name,
+			Dependent: dependent,
+		})
+	}
+	return caps
+}
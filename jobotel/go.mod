@@ -0,0 +1,19 @@
+module github.com/leyi-lee/job/jobotel
+
+go 1.23.6
+
+require (
+	github.com/leyi-lee/job v0.0.0-20261016103921-7cde743787fe
+	github.com/stretchr/testify v1.11.1
+	go.opentelemetry.io/otel v1.38.0
+	go.opentelemetry.io/otel/metric v1.38.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// 仅用于在本仓库中开发：依赖 jobotel 的其他模块会忽略 replace，按上面要求的版本取根模块
+replace github.com/leyi-lee/job => ../
@@ -0,0 +1,65 @@
+// Package jobotel 将任务组的计数指标上报到 OpenTelemetry，不使用 OpenTelemetry 时无需引入
+package jobotel
+
+import (
+	"context"
+	"sync"
+
+	"github.com/leyi-lee/job"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Meter 基于 metric.Meter 实现 job.Meter，每个指标名对应一个 Int64Counter，首次上报时创建
+type Meter struct {
+	meter metric.Meter
+
+	mu       sync.Mutex
+	counters map[string]metric.Int64Counter
+}
+
+// NewMeter 创建上报到 m 的 Meter，m 为 nil 时返回 nil（配合 job.WithMeter 即不上报）
+func NewMeter(m metric.Meter) *Meter {
+	if m == nil {
+		return nil
+	}
+	return &Meter{meter: m, counters: make(map[string]metric.Int64Counter)}
+}
+
+// ContextWithMeter 返回携带 m 的上下文，配合 job.WithMeterFromContext 使用，m 为 nil 时原样返回 ctx
+func ContextWithMeter(ctx context.Context, m metric.Meter) context.Context {
+	if m == nil {
+		return ctx
+	}
+	return job.ContextWithMeter(ctx, NewMeter(m))
+}
+
+// Add 实现 job.Meter，attrs 转换为字符串属性；计数器创建失败时不上报，m 为 nil 时不做任何事
+func (m *Meter) Add(ctx context.Context, name string, n int64, attrs map[string]string) {
+	if m == nil {
+		return
+	}
+	counter := m.counter(name)
+	if counter == nil {
+		return
+	}
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for k, v := range attrs {
+		kvs = append(kvs, attribute.String(k, v))
+	}
+	counter.Add(ctx, n, metric.WithAttributes(kvs...))
+}
+
+func (m *Meter) counter(name string) metric.Int64Counter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if c, ok := m.counters[name]; ok {
+		return c
+	}
+	c, err := m.meter.Int64Counter(name)
+	if err != nil {
+		c = nil
+	}
+	m.counters[name] = c
+	return c
+}
@@ -0,0 +1,79 @@
+package jobotel
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/leyi-lee/job"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// recordMeter 记录每个计数器按 status 属性累加的值
+type recordMeter struct {
+	noop.Meter
+
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+type recordCounter struct {
+	noop.Int64Counter
+	name  string
+	meter *recordMeter
+}
+
+func (m *recordMeter) Int64Counter(name string, _ ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return &recordCounter{name: name, meter: m}, nil
+}
+
+func (c *recordCounter) Add(_ context.Context, n int64, opts ...metric.AddOption) {
+	set := metric.NewAddConfig(opts).Attributes()
+	status, _ := set.Value(attribute.Key("status"))
+	c.meter.mu.Lock()
+	defer c.meter.mu.Unlock()
+	c.meter.counts[c.name+"/"+status.AsString()] += n
+}
+
+func (m *recordMeter) get(key string) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counts[key]
+}
+
+func TestMeter(t *testing.T) {
+	as := assert.New(t)
+
+	rec := &recordMeter{counts: make(map[string]int64)}
+	tg := job.NewTaskGroup("otel", job.WithCollectRet(), job.WithDuration(time.Second), job.WithMeterFromContext())
+	tg.WithContext(ContextWithMeter(context.Background(), rec))
+	tg.AddTaskFunc(func() (interface{}, error) { return "ok", nil })
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, errors.New("failed") })
+
+	_, err := tg.Execute()
+	as.NoError(err)
+	as.Eventually(func() bool {
+		return rec.get(job.MetricTaskTotal+"/succeeded") == 1 && rec.get(job.MetricTaskTotal+"/failed") == 1
+	}, time.Second, time.Millisecond)
+	as.Equal(int64(1), rec.get(job.MetricTaskErrors+"/failed"))
+	as.Zero(rec.get(job.MetricTaskErrors + "/succeeded"))
+}
+
+func TestNilMeter(t *testing.T) {
+	as := assert.New(t)
+
+	as.Nil(NewMeter(nil))
+	ctx := context.Background()
+	as.Equal(ctx, ContextWithMeter(ctx, nil))
+
+	tg := job.NewTaskGroup("otel_nil", job.WithCollectRet(), job.WithDuration(time.Second), job.WithMeter(NewMeter(nil)))
+	tg.AddTaskFunc(func() (interface{}, error) { return "ok", nil })
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Len(ret, 1)
+}
@@ -0,0 +1,99 @@
+// Package jobtest 提供测试替身，便于确定性地测试使用任务组的代码
+package jobtest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/leyi-lee/job"
+)
+
+// FakeGroup 按任务下标返回预设结果的任务组，不会真正执行任务
+// 方法集与 job.Group 的添加/执行方法一致，调用方依赖小接口时可直接替换
+type FakeGroup struct {
+	mu sync.Mutex
+
+	tasks    []job.Tasker
+	results  map[int]job.Result
+	timeouts map[int]bool
+	err      error
+}
+
+// NewFakeGroup 创建按下标返回 results 的任务组，未预设的下标返回零值 Result
+func NewFakeGroup(results map[int]job.Result) *FakeGroup {
+	return &FakeGroup{
+		results:  results,
+		timeouts: make(map[int]bool),
+	}
+}
+
+// Timeout 模拟下标为 indices 的任务超时：不出现在结果中，并调用其 TimeoutHandler
+func (g *FakeGroup) Timeout(indices ...int) *FakeGroup {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, i := range indices {
+		g.timeouts[i] = true
+	}
+	return g
+}
+
+// Fail 模拟配置错误，执行时直接返回 err
+func (g *FakeGroup) Fail(err error) *FakeGroup {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.err = err
+	return g
+}
+
+func (g *FakeGroup) AddTask(t job.Tasker) {
+	g.AddTasks([]job.Tasker{t})
+}
+
+func (g *FakeGroup) AddTasks(tasks []job.Tasker) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.tasks = append(g.tasks, tasks...)
+}
+
+func (g *FakeGroup) AddTaskFunc(fn job.TaskFunc) {
+	g.AddTasks([]job.Tasker{fn})
+}
+
+func (g *FakeGroup) Reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.tasks = nil
+}
+
+// Execute 按任务下标顺序返回预设结果
+func (g *FakeGroup) Execute() ([]job.Result, error) {
+	grs := <-g.ExecChan()
+	return grs.Results, grs.Error
+}
+
+func (g *FakeGroup) ExecChan() <-chan job.GroupResult {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ch := make(chan job.GroupResult, 1)
+	defer close(ch)
+	if g.err != nil {
+		ch <- job.GroupResult{Error: g.err}
+		return ch
+	}
+
+	results := make([]job.Result, 0, len(g.tasks))
+	for i, t := range g.tasks {
+		if g.timeouts[i] {
+			if out, ok := t.(job.TaskTimeout); ok {
+				out.TimeoutHandler(nil, context.DeadlineExceeded)
+			}
+			continue
+		}
+		results = append(results, g.results[i])
+	}
+	ch <- job.GroupResult{Results: results}
+	return ch
+}
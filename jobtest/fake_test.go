@@ -0,0 +1,49 @@
+package jobtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/leyi-lee/job"
+	"github.com/stretchr/testify/assert"
+)
+
+type timeoutTask struct {
+	err error
+}
+
+func (t *timeoutTask) Execute() (interface{}, error) {
+	panic("should not run")
+}
+
+func (t *timeoutTask) TimeoutHandler(ret interface{}, err error) {
+	t.err = err
+}
+
+func TestFakeGroup(t *testing.T) {
+	as := assert.New(t)
+
+	failed := errors.New("failed")
+	g := NewFakeGroup(map[int]job.Result{
+		0: {Value: "a"},
+		1: {Error: failed},
+		2: {Value: "c"},
+	}).Timeout(2)
+
+	timeout := &timeoutTask{}
+	g.AddTask(&timeoutTask{})
+	g.AddTask(&timeoutTask{})
+	g.AddTask(timeout)
+	g.AddTaskFunc(func() (interface{}, error) { panic("should not run") })
+
+	ret, err := g.Execute()
+	as.NoError(err)
+	as.Equal([]job.Result{{Value: "a"}, {Error: failed}, {}}, ret)
+	as.Equal(context.DeadlineExceeded, timeout.err)
+
+	g.Fail(failed)
+	ret, err = g.Execute()
+	as.Equal(failed, err)
+	as.Nil(ret)
+}
@@ -0,0 +1,95 @@
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// resultJSON Result 的 JSON 形式
+type resultJSON struct {
+	Index         *int        `json:"index,omitempty"`
+	Value         interface{} `json:"value"`
+	Error         string      `json:"error,omitempty"`
+	OriginalError string      `json:"original_error,omitempty"`
+	Status        string      `json:"status"`
+	Stage         int         `json:"stage,omitempty"`
+	EncodeError   string      `json:"encode_error,omitempty"`
+}
+
+func newResultJSON(r Result) resultJSON {
+	j := resultJSON{Value: r.Value, Status: r.Status.String(), Stage: r.Stage}
+	if r.Error != nil {
+		j.Error = r.Error.Error()
+	}
+	if r.OriginalError != nil {
+		j.OriginalError = r.OriginalError.Error()
+	}
+	return j
+}
+
+// MarshalJSON 编码为 {"value":...,"error":"...","original_error":"...","status":"succeeded","stage":1}，
+// 错误编码为其 Error() 文本，没有错误时省略；Status 编码为 String() 的文本；Stage 为 0 时省略
+func (r Result) MarshalJSON() ([]byte, error) {
+	return json.Marshal(newResultJSON(r))
+}
+
+// ExecuteJSON 执行所有任务，每个结果到达时以 NDJSON 格式（每行一个 JSON 对象）写入 w，不在内存中保存结果
+// 每行为 {"index":0,"value":...,"error":"...","original_error":"...","status":"failed"}，index 为任务下标，
+// 其余字段同 Result.MarshalJSON；Value 无法编码为 JSON 时 value 为 null，encode_error 为编码错误
+// w 实现 Flush() error（如 bufio.Writer）或 Flush()（如 http.Flusher）时每行写入后都会刷新
+// 写入或刷新失败时取消其余任务（走超时处理）并返回该错误；结果按完成顺序写入
+func (tg *Group) ExecuteJSON(w io.Writer) error {
+	tg.mu.Lock()
+	if len(tg.tasks) == 0 {
+		tg.runEarlyDefers()
+		tg.mu.Unlock()
+		if tg.allowEmpty {
+			return nil
+		}
+		return tg.configError(ErrNoTasks)
+	}
+	if err := tg.checkRun(); err != nil {
+		tg.runEarlyDefers()
+		tg.mu.Unlock()
+		return err
+	}
+
+	ex := tg.newExecution(tg.ctx)
+	ex.collect = false
+	ex.stream = func(tr taskResult) error {
+		return writeJSONLine(w, tr)
+	}
+	tg.run(ex)
+	tg.mu.Unlock()
+
+	defer ex.cancel()
+	grs := tg.groupResult(ex, tg.collectResults(ex))
+	if ex.streamErr != nil {
+		return fmt.Errorf("write results: %w", ex.streamErr)
+	}
+	return grs.Error
+}
+
+// writeJSONLine 写入一行结果并刷新
+func writeJSONLine(w io.Writer, tr taskResult) error {
+	line := newResultJSON(tr.Result)
+	line.Index = &tr.index
+	b, err := json.Marshal(line)
+	if err != nil {
+		line.Value, line.EncodeError = nil, err.Error()
+		if b, err = json.Marshal(line); err != nil {
+			return err
+		}
+	}
+	if _, err := w.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	switch f := w.(type) {
+	case interface{ Flush() error }:
+		return f.Flush()
+	case interface{ Flush() }:
+		f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package job
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultMarshalJSON(t *testing.T) {
+	as := assert.New(t)
+
+	b, err := json.Marshal(Result{Value: 1})
+	as.NoError(err)
+	as.JSONEq(`{"value":1,"status":"succeeded"}`, string(b))
+
+	b, err = json.Marshal(Result{Error: errors.New("failed"), Status: StatusFailed})
+	as.NoError(err)
+	as.JSONEq(`{"value":null,"error":"failed","status":"failed"}`, string(b))
+}
+
+func TestExecuteJSON(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("json", WithDuration(time.Second))
+	tg.AddTask(newTestSt("normal", 0, true))
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, errors.New("failed") })
+	tg.AddTaskFunc(func() (interface{}, error) { return make(chan int), nil })
+
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+	as.NoError(tg.ExecuteJSON(w))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	as.Len(lines, 3)
+	byIndex := make(map[int]map[string]interface{})
+	for _, line := range lines {
+		var m map[string]interface{}
+		as.NoError(json.Unmarshal([]byte(line), &m))
+		byIndex[int(m["index"].(float64))] = m
+	}
+	as.Equal("normal", byIndex[0]["value"])
+	as.Equal("failed", byIndex[1]["error"])
+	as.Equal("failed", byIndex[1]["status"])
+	as.Nil(byIndex[2]["value"])
+	as.NotEmpty(byIndex[2]["encode_error"])
+}
+
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestExecuteJSONWriteError(t *testing.T) {
+	as := assert.New(t)
+
+	slow := newTimeoutSt("slow", 100*time.Millisecond)
+	tg := NewTaskGroup("json_error", WithDuration(time.Second))
+	tg.AddTask(newTestSt("normal", 0, true))
+	tg.AddTask(slow)
+
+	start := time.Now()
+	err := tg.ExecuteJSON(failWriter{})
+	as.ErrorContains(err, "broken pipe")
+	as.Less(time.Since(start), 100*time.Millisecond)
+	as.Equal("slow", <-slow.timedOut) // 写入失败时取消其余任务
+}
@@ -0,0 +1,69 @@
+package job
+
+import "time"
+
+// LabeledTask 为任务附加标签，标签出现在指标回调和日志中，不影响执行
+type LabeledTask struct {
+	Tasker
+	Labels map[string]string
+}
+
+// Labeled 为任务 t 附加标签
+func Labeled(t Tasker, labels map[string]string) *LabeledTask {
+	return &LabeledTask{Tasker: t, Labels: labels}
+}
+
+// Unwrap 返回被包装的任务，组通过它识别被包装任务实现的可选接口
+func (l *LabeledTask) Unwrap() Tasker {
+	return l.Tasker
+}
+
+// taskAs 沿 Unwrap 链查找实现了 T 的任务，类似 errors.As
+func taskAs[T any](t Tasker) (T, bool) {
+	for t != nil {
+		if v, ok := t.(T); ok {
+			return v, true
+		}
+		u, ok := t.(interface{ Unwrap() Tasker })
+		if !ok {
+			break
+		}
+		t = u.Unwrap()
+	}
+	var zero T
+	return zero, false
+}
+
+// taskLabels 返回任务的标签
+func taskLabels(t Tasker) map[string]string {
+	if l, ok := taskAs[*LabeledTask](t); ok {
+		return l.Labels
+	}
+	return nil
+}
+
+// TaskMetric 单个任务结束时的指标
+type TaskMetric struct {
+	Group    string
+	RunID    string // WithRunID 设置或生成的执行 ID
+	Index    int
+	Labels   map[string]string
+	Status   TaskStatus
+	Error    error
+	TimedOut bool // 结果走了超时处理（超时、被取消或异步执行）
+	Duration time.Duration
+	// QueueWait 等待执行空位（WithMaxConcurrency 等）的时长，包含在 Duration 中，
+	// 用于区分系统饱和与任务执行慢；没有并发限制时为 0
+	QueueWait time.Duration
+}
+
+type metricsHookOption func(TaskMetric)
+
+func (m metricsHookOption) bind(o *options) {
+	o.MetricsHook = m
+}
+
+// WithMetricsHook 每个任务结束时在任务协程中调用 fn，可按标签聚合耗时等指标
+func WithMetricsHook(fn func(TaskMetric)) Option {
+	return metricsHookOption(fn)
+}
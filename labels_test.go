@@ -0,0 +1,92 @@
+package job
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabeledTaskMetrics(t *testing.T) {
+	as := assert.New(t)
+
+	var mu sync.Mutex
+	metrics := make(map[int]TaskMetric)
+	log := &memLog{}
+	tg := NewTaskGroup("labels", WithCollectRet(), WithDuration(50*time.Millisecond), WithLog(log),
+		WithMetricsHook(func(m TaskMetric) {
+			mu.Lock()
+			defer mu.Unlock()
+			metrics[m.Index] = m
+		}))
+
+	timeout := newTimeoutSt("timeout", 100*time.Millisecond)
+	tg.AddTask(Labeled(newTestSt("db", 0, true), map[string]string{"kind": "db"}))
+	tg.AddTask(Labeled(timeout, map[string]string{"kind": "rpc"}))
+	tg.AddTask(Labeled(TaskFunc(func() (interface{}, error) { panic("boom") }), map[string]string{"kind": "cpu"}))
+	tg.AddTask(newTestSt("plain", 0, true))
+
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.ElementsMatch([]Result{{Value: "db"}, {Value: "plain"}}, ret)
+
+	// 被包装任务的 TimeoutHandler 仍被调用
+	as.Equal("timeout", <-timeout.timedOut)
+	time.Sleep(10 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	as.Len(metrics, 4)
+	as.Equal(map[string]string{"kind": "db"}, metrics[0].Labels)
+	as.Equal(StatusSucceeded, metrics[0].Status)
+	as.True(metrics[1].TimedOut)
+	as.GreaterOrEqual(metrics[1].Duration, 100*time.Millisecond)
+	as.Equal(StatusPanicked, metrics[2].Status)
+	as.Nil(metrics[3].Labels)
+	as.Equal("labels", metrics[3].Group)
+	as.Equal(map[string]string{"kind": "cpu"}, log.errDat[0]["labels"])
+
+	caps := tg.InspectTasks()
+	as.True(caps[1].Timeout)
+}
+
+func TestQueueWaitMetric(t *testing.T) {
+	as := assert.New(t)
+
+	run := func(opts ...Option) []time.Duration {
+		var mu sync.Mutex
+		var waits []time.Duration
+		tg := NewTaskGroup("queue_wait", append(opts, WithDuration(time.Second),
+			WithMetricsHook(func(m TaskMetric) {
+				mu.Lock()
+				waits = append(waits, m.QueueWait)
+				mu.Unlock()
+			}))...)
+		for i := 0; i < 2; i++ {
+			tg.AddTaskFunc(func() (interface{}, error) {
+				time.Sleep(20 * time.Millisecond)
+				return nil, nil
+			})
+		}
+		_, err := tg.Execute()
+		as.NoError(err)
+
+		as.Eventually(func() bool { // 指标回调在结果发送之后执行
+			mu.Lock()
+			defer mu.Unlock()
+			return len(waits) == 2
+		}, time.Second, time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		return waits
+	}
+
+	waits := run(WithMaxConcurrency(1))
+	if as.Len(waits, 2) {
+		// 第二个任务等第一个任务结束后才开始
+		as.GreaterOrEqual(waits[1], 15*time.Millisecond)
+		as.Less(waits[0], waits[1])
+	}
+	as.Equal([]time.Duration{0, 0}, run()) // 没有并发限制时为 0
+}
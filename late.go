@@ -0,0 +1,62 @@
+package job
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// LateResult 截止后才结束的任务的结果
+type LateResult struct {
+	Index int // 任务下标
+	Result
+}
+
+// WithLateResults 任务上下文结束（超时、被取消）时立即调用超时处理（ret 为 nil，err 为上下文错误），
+// 任务继续执行，结束后的结果不再走超时处理，而是发送到 LateResults 返回的通道，适合先响应、稍后缓存结果的场景
+// 未设置等待时长时不生效
+func WithLateResults() Option {
+	return lateResultsOption(true)
+}
+
+type lateResultsOption bool
+
+func (l lateResultsOption) bind(o *options) {
+	o.LateResults = bool(l)
+}
+
+// LateResults 返回最近一次执行的迟到结果通道，所有任务结束后关闭，应在执行返回后调用
+// 没有设置 WithLateResults 或没有执行过时返回 nil
+func (tg *Group) LateResults() <-chan LateResult {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+
+	if tg.cur == nil || tg.cur.late == nil {
+		return nil
+	}
+	return tg.cur.late
+}
+
+// newLateResults 为本次执行创建迟到结果通道，缓存与任务数相同，发送不会阻塞
+func (tg *Group) newLateResults(ex *execution) {
+	if !tg.lateResults || !tg.isTimeout() {
+		return
+	}
+	ex.late = make(chan LateResult, len(tg.tasks))
+	ex.lateFired = make([]int32, len(tg.tasks))
+}
+
+// timeoutAtDeadline 任务上下文结束时仍未结束的任务立即走超时处理
+func (tg *Group) timeoutAtDeadline(ex *execution, ctx context.Context, t Tasker, i int) {
+	if atomic.CompareAndSwapInt32(&ex.lateFired[i], 0, 1) {
+		tg.handleTimeout(ctx, t, Result{Error: ctx.Err(), Status: StatusTimedOut})
+	}
+}
+
+// deliverLate 任务已在截止时走过超时处理时将结果发送为迟到结果，返回是否已发送
+func (ex *execution) deliverLate(i int, ret Result) bool {
+	if ex.late == nil || atomic.CompareAndSwapInt32(&ex.lateFired[i], 0, 1) {
+		return false
+	}
+	ex.late <- LateResult{Index: i, Result: ret}
+	return true
+}
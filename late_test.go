@@ -0,0 +1,75 @@
+package job
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// slowSt 执行 d 后返回 value，记录超时处理
+type slowSt struct {
+	d       time.Duration
+	value   int
+	handled chan error
+}
+
+func (s *slowSt) Execute() (interface{}, error) {
+	time.Sleep(s.d)
+	return s.value, nil
+}
+
+func (s *slowSt) TimeoutHandler(ret interface{}, err error) {
+	s.handled <- err
+}
+
+func TestLateResults(t *testing.T) {
+	as := assert.New(t)
+
+	fast := &slowSt{value: 1, handled: make(chan error, 1)}
+	slow := &slowSt{d: 150 * time.Millisecond, value: 2, handled: make(chan error, 1)}
+	tg := NewTaskGroup("late", WithDuration(50*time.Millisecond), WithCollectRet(), WithLateResults())
+	tg.AddTask(fast)
+	tg.AddTask(slow)
+
+	start := time.Now()
+	results, err := tg.Execute()
+	as.NoError(err)
+	as.Equal([]Result{{Value: 1, Status: StatusSucceeded}}, results)
+
+	// 截止时立即调用超时处理，不等任务结束
+	select {
+	case err := <-slow.handled:
+		as.ErrorIs(err, context.DeadlineExceeded)
+		as.Less(time.Since(start), 140*time.Millisecond)
+	case <-time.After(time.Second):
+		as.Fail("timeout handler not called at deadline")
+	}
+
+	late := tg.LateResults()
+	as.Equal(LateResult{Index: 1, Result: Result{Value: 2, Status: StatusSucceeded}}, <-late)
+	_, ok := <-late
+	as.False(ok) // 所有任务结束后关闭
+
+	as.Empty(slow.handled) // 迟到结果不再走超时处理
+	as.Empty(fast.handled)
+}
+
+func TestLateResultsDisabled(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("late_off", WithDuration(time.Second))
+	as.Nil(tg.LateResults())
+	tg.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+	_, err := tg.Execute()
+	as.NoError(err)
+	as.Nil(tg.LateResults())
+
+	async := NewTaskGroup("late_async", WithLateResults())
+	async.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+	_, err = async.Execute()
+	as.NoError(err)
+	as.Nil(async.LateResults()) // 没有等待时长不生效
+	time.Sleep(10 * time.Millisecond)
+}
@@ -0,0 +1,52 @@
+package job
+
+import (
+	"fmt"
+	"os"
+)
+
+// multiLogger 将日志分发给多个 Logger
+type multiLogger []Logger
+
+// MultiLogger 返回把 Info/Error 依次分发给 loggers 的 Logger
+// 某个 Logger panic 时会被恢复，不影响其他 Logger 接收日志
+func MultiLogger(loggers ...Logger) Logger {
+	return multiLogger(loggers)
+}
+
+func (m multiLogger) Info(message string, data map[string]interface{}) {
+	for _, l := range m {
+		func() {
+			defer func() { _ = recover() }()
+			l.Info(message, data)
+		}()
+	}
+}
+
+func (m multiLogger) Error(message string, err error, data map[string]interface{}) {
+	for _, l := range m {
+		func() {
+			defer func() { _ = recover() }()
+			l.Error(message, err, data)
+		}()
+	}
+}
+
+// logInfo 调用 log.Info，日志实现自身的 panic 被恢复并输出到标准错误，不影响任务组
+func logInfo(log Logger, message string, data map[string]interface{}) {
+	defer recoverLog(message)
+	log.Info(message, data)
+}
+
+// logError 调用 log.Error，日志实现自身的 panic 被恢复并输出到标准错误，不影响任务组
+func logError(log Logger, message string, err error, data map[string]interface{}) {
+	defer recoverLog(message)
+	log.Error(message, err, data)
+}
+
+// recoverLog 恢复日志实现的 panic，只能直接 defer 调用
+func recoverLog(message string) {
+	if r := recover(); r != nil {
+		fmt.Fprintf(os.Stderr, "job: logger panic while logging %q: %v\n", message, r)
+	}
+}
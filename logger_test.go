@@ -0,0 +1,45 @@
+package job
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type panicLog struct{}
+
+func (panicLog) Info(message string, data map[string]interface{}) {
+	panic("info")
+}
+
+func (panicLog) Error(message string, err error, data map[string]interface{}) {
+	panic("error")
+}
+
+func TestMultiLogger(t *testing.T) {
+	as := assert.New(t)
+
+	a, b := &memLog{}, &memLog{}
+	log := MultiLogger(a, panicLog{}, b)
+	log.Info("info", nil)
+	log.Error("error", errors.New("failed"), nil)
+
+	for _, l := range []*memLog{a, b} {
+		as.Equal([]string{"info"}, l.infos)
+		as.Equal([]string{"error"}, l.errs)
+	}
+}
+
+func TestPanickingLogger(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("panic_logger", WithCollectRet(), WithDuration(100*time.Millisecond),
+		WithLog(panicLog{}), WithHeartbeat(10*time.Millisecond))
+	tg.AddTaskFunc(func() (interface{}, error) { panic("boom") })
+	tg.AddTask(newTestSt("slow", 30*time.Millisecond, true))
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Equal([]Result{{Value: "slow"}}, ret)
+}
@@ -0,0 +1,106 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// MemoizedTask 在 TTL 内缓存任务结果的包装，由 MemoTask 创建；同一个实例可以添加到多个任务组或多次执行中，
+// 缓存有效时直接返回缓存的结果而不执行任务，适合多次执行的模板任务组中昂贵且很少变化的任务
+// 没有缓存时并发的执行只有一个真正执行任务，其余等待它的结果，等待期间各自的上下文结束时返回上下文的取消原因；
+// 默认只缓存成功的结果，CacheErrors 为 true 时同时缓存失败
+type MemoizedTask struct {
+	Tasker
+	TTL         time.Duration
+	CacheErrors bool
+
+	mu      sync.Mutex
+	value   interface{}
+	err     error
+	expires time.Time // 零值表示没有缓存
+	call    *memoCall // 正在执行的调用，没有时为 nil
+}
+
+// memoCall 一次正在执行的调用，done 关闭后 value、err 可读
+type memoCall struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// errMemoPanicked 执行任务的调用 panic 时，等待它的调用得到的错误
+var errMemoPanicked = errors.New("memoized task panicked")
+
+// MemoTask 把任务 t 的结果缓存 ttl，ttl <= 0 时不缓存，每次执行都直接执行任务
+func MemoTask(ttl time.Duration, t Tasker) *MemoizedTask {
+	return &MemoizedTask{Tasker: t, TTL: ttl}
+}
+
+// Unwrap 返回被包装的任务，组通过它识别被包装任务实现的可选接口
+func (m *MemoizedTask) Unwrap() Tasker {
+	return m.Tasker
+}
+
+func (m *MemoizedTask) Execute() (interface{}, error) {
+	return m.memo(context.Background(), m.Tasker.Execute)
+}
+
+// ExecuteCtx 被包装的任务实现 ContextTasker 时以 ctx 执行，否则调用 Execute
+func (m *MemoizedTask) ExecuteCtx(ctx context.Context) (interface{}, error) {
+	if ct, ok := taskAs[ContextTasker](m.Tasker); ok {
+		return m.memo(ctx, func() (interface{}, error) { return ct.ExecuteCtx(ctx) })
+	}
+	return m.memo(ctx, m.Tasker.Execute)
+}
+
+// Invalidate 清除缓存，下次执行时重新执行任务
+func (m *MemoizedTask) Invalidate() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.value, m.err, m.expires = nil, nil, time.Time{}
+}
+
+// memo 缓存有效时返回缓存的结果，已有调用正在执行时等待它的结果（ctx 先结束时返回取消原因），
+// 否则调用 exec 并按配置缓存结果；TTL <= 0 时直接调用 exec
+func (m *MemoizedTask) memo(ctx context.Context, exec func() (interface{}, error)) (interface{}, error) {
+	if m.TTL <= 0 {
+		return exec()
+	}
+
+	m.mu.Lock()
+	if !m.expires.IsZero() && time.Now().Before(m.expires) {
+		defer m.mu.Unlock()
+		return m.value, m.err
+	}
+	if c := m.call; c != nil {
+		m.mu.Unlock()
+		select {
+		case <-c.done:
+			return c.value, c.err
+		case <-ctx.Done():
+			return nil, context.Cause(ctx)
+		}
+	}
+	c := &memoCall{done: make(chan struct{}), err: errMemoPanicked}
+	m.call = c
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		if m.call == c {
+			m.call = nil
+		}
+		m.mu.Unlock()
+		close(c.done)
+	}()
+	c.value, c.err = exec()
+
+	m.mu.Lock()
+	if c.err == nil || m.CacheErrors {
+		m.value, m.err, m.expires = c.value, c.err, time.Now().Add(m.TTL)
+	}
+	m.mu.Unlock()
+	return c.value, c.err
+}
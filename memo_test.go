@@ -0,0 +1,108 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoTask(t *testing.T) {
+	as := assert.New(t)
+
+	var calls int32
+	task := MemoTask(50*time.Millisecond, TaskFunc(func() (interface{}, error) {
+		return atomic.AddInt32(&calls, 1), nil
+	}))
+	run := func() Result {
+		tg := NewTaskGroup("memo", WithDuration(time.Second), WithCollectRet())
+		tg.AddTask(task)
+		tg.AddTask(task) // 并发执行时只执行一次
+		results, err := tg.Execute()
+		as.NoError(err)
+		as.Len(results, 2)
+		as.Equal(results[0], results[1])
+		return results[0]
+	}
+
+	as.Equal(Result{Value: int32(1)}, run())
+	as.Equal(Result{Value: int32(1)}, run())
+	time.Sleep(60 * time.Millisecond)
+	as.Equal(Result{Value: int32(2)}, run())
+	task.Invalidate()
+	as.Equal(Result{Value: int32(3)}, run())
+}
+
+func TestMemoTaskErrors(t *testing.T) {
+	as := assert.New(t)
+
+	var calls int32
+	fail := TaskFunc(func() (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, errors.New("failed")
+	})
+
+	task := MemoTask(time.Minute, fail)
+	_, err := task.Execute()
+	as.Error(err)
+	_, err = task.Execute()
+	as.Error(err)
+	as.Equal(int32(2), atomic.LoadInt32(&calls)) // 默认不缓存失败
+
+	task = MemoTask(time.Minute, fail)
+	task.CacheErrors = true
+	_, _ = task.Execute()
+	_, err = task.Execute()
+	as.EqualError(err, "failed")
+	as.Equal(int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestMemoTaskConcurrency(t *testing.T) {
+	as := assert.New(t)
+
+	// ttl <= 0 不缓存，也不串行化并发执行
+	slow := TaskFunc(func() (interface{}, error) {
+		time.Sleep(100 * time.Millisecond)
+		return "slow", nil
+	})
+	task := MemoTask(0, slow)
+	tg := NewTaskGroup("memo_nocache", WithDuration(time.Second), WithCollectRet())
+	for i := 0; i < 5; i++ {
+		tg.AddTask(task)
+	}
+	start := time.Now()
+	results, err := tg.Execute()
+	as.NoError(err)
+	as.Len(results, 5)
+	as.Less(time.Since(start), 300*time.Millisecond)
+
+	// 等待正在执行的调用时各自的上下文结束即返回
+	task = MemoTask(time.Minute, slow)
+	go func() { _, _ = task.Execute() }()
+	time.Sleep(10 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	_, err = task.ExecuteCtx(ctx)
+	as.ErrorIs(err, context.DeadlineExceeded)
+	as.Less(time.Since(start), 80*time.Millisecond)
+	v, err := task.ExecuteCtx(context.Background()) // 等到正在执行的调用结束
+	as.NoError(err)
+	as.Equal("slow", v)
+
+	// 执行的调用 panic 时等待方得到错误
+	task = MemoTask(time.Minute, TaskFunc(func() (interface{}, error) {
+		time.Sleep(30 * time.Millisecond)
+		panic("boom")
+	}))
+	go func() {
+		defer func() { _ = recover() }()
+		_, _ = task.Execute()
+	}()
+	time.Sleep(10 * time.Millisecond)
+	_, err = task.Execute()
+	as.ErrorIs(err, errMemoPanicked)
+}
@@ -0,0 +1,71 @@
+package job
+
+import "context"
+
+// 任务组上报的计数指标名称
+const (
+	MetricTaskTotal  = "task_total"  // 每个结束的任务加 1，带 status 属性
+	MetricTaskErrors = "task_errors" // 每个失败或 panic 的任务加 1，带 status 属性
+)
+
+// Meter 任务组上报计数指标的接口，attrs 包含组名 group 和任务状态 status
+// 在任务协程中调用，需并发安全；jobotel 子包提供基于 OpenTelemetry metric.Meter 的实现
+type Meter interface {
+	Add(ctx context.Context, name string, n int64, attrs map[string]string)
+}
+
+type meterKey struct{}
+
+// ContextWithMeter 返回携带 m 的上下文，配合 WithMeterFromContext 使用
+func ContextWithMeter(ctx context.Context, m Meter) context.Context {
+	return context.WithValue(ctx, meterKey{}, m)
+}
+
+// MeterFromContext 返回 ctx 携带的 Meter，没有时返回 nil
+func MeterFromContext(ctx context.Context) Meter {
+	m, _ := ctx.Value(meterKey{}).(Meter)
+	return m
+}
+
+// WithMeter 每个任务结束时向 m 上报 MetricTaskTotal，失败或 panic 的任务同时上报 MetricTaskErrors；
+// 超时或被取消时任务按其最终状态上报；m 为 nil 时不上报
+func WithMeter(m Meter) Option {
+	return meterOption{m}
+}
+
+type meterOption struct {
+	Meter
+}
+
+func (m meterOption) bind(o *options) {
+	o.Meter = m.Meter
+}
+
+// WithMeterFromContext 每次执行开始时从组上下文（WithContext 或 ExecuteDeadline 传入的上下文）中取出
+// ContextWithMeter 设置的 Meter 上报指标，同 WithMeter；同时设置 WithMeter 时以 WithMeter 为准，上下文中没有时不上报
+func WithMeterFromContext() Option {
+	return meterFromContextOption(true)
+}
+
+type meterFromContextOption bool
+
+func (m meterFromContextOption) bind(o *options) {
+	o.MeterFromContext = bool(m)
+}
+
+// meterFor 本次执行使用的 Meter
+func (tg *Group) meterFor(parent context.Context) Meter {
+	if tg.meter != nil || !tg.meterFromContext || parent == nil {
+		return tg.meter
+	}
+	return MeterFromContext(parent)
+}
+
+// countMetrics 上报任务结束时的计数指标
+func (ex *execution) countMetrics(ctx context.Context, s TaskStatus) {
+	attrs := map[string]string{"group": ex.name, "status": s.String()}
+	ex.meter.Add(ctx, MetricTaskTotal, 1, attrs)
+	if s == StatusFailed || s == StatusPanicked {
+		ex.meter.Add(ctx, MetricTaskErrors, 1, attrs)
+	}
+}
@@ -0,0 +1,79 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeMeter struct {
+	mu     sync.Mutex
+	counts map[string]int64 // name/status -> 计数
+}
+
+func (m *fakeMeter) Add(_ context.Context, name string, n int64, attrs map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.counts == nil {
+		m.counts = make(map[string]int64)
+	}
+	m.counts[name+"/"+attrs["status"]] += n
+	m.counts[name] += n
+}
+
+func (m *fakeMeter) get(key string) int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counts[key]
+}
+
+func TestMeter(t *testing.T) {
+	as := assert.New(t)
+
+	meter := &fakeMeter{}
+	tg := NewTaskGroup("meter", WithCollectRet(), WithDuration(time.Second), WithMeter(meter), WithLog(&memLog{}))
+	tg.AddTask(newTestSt("normal", 0, true))
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, errors.New("failed") })
+	tg.AddTaskFunc(func() (interface{}, error) { panic("boom") })
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, Skip() })
+
+	_, err := tg.Execute()
+	as.NoError(err)
+	as.Eventually(func() bool { return meter.get(MetricTaskTotal) == 4 }, time.Second, time.Millisecond)
+	as.Equal(int64(1), meter.get(MetricTaskTotal+"/succeeded"))
+	as.Equal(int64(1), meter.get(MetricTaskTotal+"/skipped"))
+	as.Equal(int64(2), meter.get(MetricTaskErrors))
+	as.Equal(int64(1), meter.get(MetricTaskErrors+"/failed"))
+	as.Equal(int64(1), meter.get(MetricTaskErrors+"/panicked"))
+}
+
+func TestMeterFromContext(t *testing.T) {
+	as := assert.New(t)
+
+	meter := &fakeMeter{}
+	tg := NewTaskGroup("meter_ctx", WithCollectRet(), WithDuration(time.Second), WithMeterFromContext())
+	tg.AddTask(newTestSt("normal", 0, true))
+
+	// 上下文中没有 Meter 时不上报
+	_, err := tg.Execute()
+	as.NoError(err)
+
+	tg.WithContext(ContextWithMeter(context.Background(), meter))
+	_, err = tg.Execute()
+	as.NoError(err)
+	as.Eventually(func() bool { return meter.get(MetricTaskTotal) == 1 }, time.Second, time.Millisecond)
+
+	// 未设置 WithMeterFromContext 时忽略上下文中的 Meter
+	other := &fakeMeter{}
+	tg = NewTaskGroup("meter_ctx", WithCollectRet(), WithDuration(time.Second))
+	tg.AddTask(newTestSt("normal", 0, true))
+	tg.WithContext(ContextWithMeter(context.Background(), other))
+	_, err = tg.Execute()
+	as.NoError(err)
+	as.Zero(other.get(MetricTaskTotal))
+	as.Nil(MeterFromContext(context.Background()))
+}
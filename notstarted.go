@@ -0,0 +1,24 @@
+package job
+
+import "sync/atomic"
+
+// markStarted 记录任务 i 已开始执行
+func (ex *execution) markStarted(i int) {
+	atomic.StoreInt32(&ex.started[i], 1)
+}
+
+// notStarted 返回收集结束时从未开始执行的任务下标，以及其中命名任务（AddNamedTask、AddDependentTask）的名称
+func (ex *execution) notStarted() ([]int, []string) {
+	var indices []int
+	var names []string
+	for i := range ex.started {
+		if atomic.LoadInt32(&ex.started[i]) == 1 {
+			continue
+		}
+		indices = append(indices, i)
+		if ex.slotOf != nil && ex.slotOf[i] != nil {
+			names = append(names, ex.slotOf[i].name)
+		}
+	}
+	return indices, names
+}
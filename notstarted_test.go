@@ -0,0 +1,35 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotStarted(t *testing.T) {
+	as := assert.New(t)
+
+	release := make(chan struct{})
+	defer close(release)
+	tg := NewTaskGroup("not_started", WithDuration(30*time.Millisecond), WithCollectRet(), WithSequential())
+	tg.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+	tg.AddNamedTask("slow", TaskFunc(func() (interface{}, error) {
+		<-release
+		return 2, nil
+	}))
+	tg.AddNamedTask("queued", TaskFunc(func() (interface{}, error) { return 3, nil }))
+	tg.AddTaskFunc(func() (interface{}, error) { return 4, nil })
+
+	grs := <-tg.ExecChan()
+	as.NoError(grs.Error)
+	as.Len(grs.Results, 1)
+	as.Equal([]int{2, 3}, grs.NotStarted) // 超时的任务 1 已开始执行，不计入
+	as.Equal([]string{"queued"}, grs.NotStartedNames)
+
+	tg = NewTaskGroup("all_started", WithDuration(time.Second), WithCollectRet())
+	tg.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+	grs = <-tg.ExecChan()
+	as.Nil(grs.NotStarted)
+	as.Nil(grs.NotStartedNames)
+}
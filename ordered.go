@@ -0,0 +1,98 @@
+package job
+
+import "context"
+
+// orderedSink 缓存乱序到达的结果，按下标顺序回调连续完成的前缀
+type orderedSink struct {
+	fn      func(int, Result)
+	next    int            // 下一个应回调的下标
+	pending map[int]Result // 已到达但前面仍有任务未交付的结果
+}
+
+func newOrderedSink(fn func(int, Result), total int) *orderedSink {
+	if fn == nil {
+		return nil
+	}
+	return &orderedSink{fn: fn, pending: make(map[int]Result, total)}
+}
+
+// add 记录任务 tr.index 的结果，并回调已连续完成的前缀
+func (o *orderedSink) add(tr taskResult) {
+	o.pending[tr.index] = tr.Result
+	for {
+		r, ok := o.pending[o.next]
+		if !ok {
+			return
+		}
+		delete(o.pending, o.next)
+		o.fn(o.next, r)
+		o.next++
+	}
+}
+
+// flush 收集结束时按下标顺序回调剩余结果，跳过没有交付的任务
+func (o *orderedSink) flush(total int) {
+	for ; o.next < total && len(o.pending) > 0; o.next++ {
+		if r, ok := o.pending[o.next]; ok {
+			delete(o.pending, o.next)
+			o.fn(o.next, r)
+		}
+	}
+}
+
+// flushOrdered 回调有序 sink 中剩余的结果
+func (ex *execution) flushOrdered() {
+	if ex.ordered != nil {
+		ex.ordered.flush(ex.total)
+	}
+}
+
+// ExecuteFixed 执行所有任务，按任务下标返回结果，长度与任务数相同，第 i 个结果对应第 i 个任务
+// 无论是否设置 WithCollectRet 都会收集结果，WithResultFilter 不影响返回的结果；
+// 结束时仍未交付结果的任务：截止前 panic 的 Status 为 StatusPanicked，Error 为 *PanicError（设置了 WithRecoverer 时为其返回的结果）；
+// 其余任务超时或被取消，Status 为 StatusTimedOut，Error 为任务上下文的取消原因
+func (tg *Group) ExecuteFixed() ([]Result, error) {
+	tg.mu.Lock()
+	if len(tg.tasks) == 0 {
+		tg.runEarlyDefers()
+		tg.mu.Unlock()
+		if tg.allowEmpty {
+			return []Result{}, nil
+		}
+		return nil, tg.configError(ErrNoTasks)
+	}
+	if err := tg.checkRun(); err != nil {
+		tg.runEarlyDefers()
+		tg.mu.Unlock()
+		return nil, err
+	}
+
+	ex := tg.newExecution(tg.ctx)
+	ex.collect = false
+	fixed := make([]Result, len(tg.tasks))
+	delivered := make([]bool, len(tg.tasks))
+	ex.stream = func(tr taskResult) error {
+		fixed[tr.index], delivered[tr.index] = tr.Result, true
+		return nil
+	}
+	tg.run(ex)
+	tg.mu.Unlock()
+
+	defer ex.cancel()
+	grs := tg.groupResult(ex, tg.collectResults(ex))
+	for i := range fixed {
+		if delivered[i] {
+			continue
+		}
+		if ret, ok := ex.panicResult(i); ok {
+			fixed[i] = ret
+			continue
+		}
+		err := context.Cause(ex.ctxs[i])
+		if err == nil {
+			err = context.DeadlineExceeded
+		}
+		fixed[i] = Result{Error: err, Status: StatusTimedOut}
+	}
+	return fixed, grs.Error
+}
@@ -0,0 +1,138 @@
+package job
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Pause 暂停任务组：尚未开始执行的任务等待 Resume 后再开始，正在执行的任务不受影响
+// 暂停状态属于任务组，执行结束后仍然保留，之后的执行同样等待；重复调用无副作用
+// 暂停期间默认照常计入等待时长，设置 WithPausedTimeExcluded 后不计入
+func (tg *Group) Pause() {
+	tg.pause.pause()
+}
+
+// Resume 恢复任务组，等待中的任务继续开始执行；未暂停时无副作用
+func (tg *Group) Resume() {
+	tg.pause.resume()
+}
+
+// Paused 返回任务组是否处于暂停状态
+func (tg *Group) Paused() bool {
+	return tg.pause.wait() != nil
+}
+
+// WithPausedTimeExcluded Pause 到 Resume 之间的时间不计入等待时长，组的截止时间相应推后
+// 设置后组上下文不再带截止时间，到时后被取消，context.Cause 为 context.DeadlineExceeded
+func WithPausedTimeExcluded() Option {
+	return pausedTimeExcludedOption(true)
+}
+
+type pausedTimeExcludedOption bool
+
+func (p pausedTimeExcludedOption) bind(o *options) {
+	o.PausedTimeExcluded = bool(p)
+}
+
+// pauseState 任务组的暂停状态，同时驱动需要随暂停停止的计时器，两者在同一把锁下更新
+type pauseState struct {
+	mu      sync.Mutex
+	resumed chan struct{}  // 暂停期间非 nil，恢复时关闭
+	clock   *deadlineTimer // 最近一次执行随暂停停止的计时器（WithPausedTimeExcluded），nil 表示没有
+}
+
+// pause 进入暂停状态并停止计时，已暂停时不做任何事
+func (p *pauseState) pause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.resumed != nil {
+		return
+	}
+	p.resumed = make(chan struct{})
+	p.clock.pause()
+}
+
+// resume 解除暂停状态并继续计时，未暂停时不做任何事
+func (p *pauseState) resume() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.resumed == nil {
+		return
+	}
+	close(p.resumed)
+	p.resumed = nil
+	p.clock.resume()
+}
+
+// track 改由 clock 随暂停停止计时，暂停期间立即停止
+func (p *pauseState) track(clock *deadlineTimer) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.clock = clock
+	if p.resumed != nil {
+		clock.pause()
+	}
+}
+
+// wait 暂停期间返回恢复时关闭的通道，未暂停时返回 nil
+func (p *pauseState) wait() <-chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.resumed
+}
+
+// waitResume 暂停期间等待恢复，ctx 先结束返回 ctx.Err()
+func (ex *execution) waitResume(ctx context.Context) error {
+	for {
+		resumed := ex.pause.wait()
+		if resumed == nil {
+			return nil
+		}
+		select {
+		case <-resumed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// pause 停止计时，记下剩余时长，dt 为 nil 时不做任何事
+func (dt *deadlineTimer) pause() {
+	if dt == nil {
+		return
+	}
+	dt.mu.Lock()
+	defer dt.mu.Unlock()
+
+	if dt.paused || dt.stopped {
+		return
+	}
+	dt.paused = true
+	if dt.timer != nil && dt.timer.Stop() {
+		dt.remaining = time.Until(dt.deadline)
+		dt.held = true
+	}
+}
+
+// resume 以剩余时长继续计时
+func (dt *deadlineTimer) resume() {
+	if dt == nil {
+		return
+	}
+	dt.mu.Lock()
+	defer dt.mu.Unlock()
+
+	if !dt.paused {
+		return
+	}
+	dt.paused = false
+	if dt.held && !dt.stopped {
+		dt.held = false
+		dt.deadline = time.Now().Add(dt.remaining)
+		dt.timer.Reset(dt.remaining)
+	}
+}
@@ -0,0 +1,121 @@
+package job
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPause(t *testing.T) {
+	as := assert.New(t)
+
+	var started int32
+	release := make(chan struct{})
+	tg := NewTaskGroup("pause", WithDuration(time.Second), WithMaxConcurrency(1), WithCollectRet())
+	tg.AddTaskFunc(func() (interface{}, error) {
+		atomic.AddInt32(&started, 1)
+		<-release
+		return 1, nil
+	})
+	tg.AddTaskFunc(func() (interface{}, error) {
+		atomic.AddInt32(&started, 1)
+		<-release
+		return 2, nil
+	})
+
+	done := make(chan []Result, 1)
+	go func() {
+		results, _ := tg.Execute()
+		done <- results
+	}()
+	as.Eventually(func() bool { return atomic.LoadInt32(&started) == 1 }, time.Second, time.Millisecond)
+
+	tg.Pause()
+	as.True(tg.Paused())
+	close(release) // 正在执行的任务不受影响，下一个任务等待恢复
+	time.Sleep(30 * time.Millisecond)
+	as.EqualValues(1, atomic.LoadInt32(&started))
+
+	tg.Resume()
+	as.False(tg.Paused())
+	results := <-done
+	as.Len(results, 2)
+	as.EqualValues(2, atomic.LoadInt32(&started))
+}
+
+func TestPauseTimeout(t *testing.T) {
+	as := assert.New(t)
+
+	run := func(opts ...Option) []Result {
+		tg := NewTaskGroup("pause_timeout", append(opts, WithDuration(60*time.Millisecond), WithCollectRet())...)
+		tg.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+		tg.Pause()
+		done := make(chan []Result, 1)
+		go func() {
+			results, _ := tg.Execute()
+			done <- results
+		}()
+		time.Sleep(100 * time.Millisecond)
+		tg.Resume()
+		return <-done
+	}
+
+	as.Empty(run()) // 默认暂停时间计入等待时长，任务未开始就超时
+	as.Equal([]Result{{Value: 1, Status: StatusSucceeded}}, run(WithPausedTimeExcluded()))
+}
+
+func TestPauseResumeRace(t *testing.T) {
+	as := assert.New(t)
+
+	release := make(chan struct{})
+	tg := NewTaskGroup("pause_race", WithDuration(50*time.Millisecond), WithPausedTimeExcluded(), WithCollectRet())
+	tg.AddTaskFunc(func() (interface{}, error) {
+		<-release
+		return 1, nil
+	})
+	defer close(release)
+
+	done := make(chan []Result, 1)
+	go func() {
+		results, _ := tg.Execute()
+		done <- results
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func() { defer wg.Done(); tg.Pause() }()
+		go func() { defer wg.Done(); tg.Resume() }()
+	}
+	wg.Wait()
+	tg.Resume()
+	as.False(tg.Paused())
+
+	select {
+	case results := <-done: // 恢复后计时器一定继续计时，等待时长到达
+		as.Empty(results)
+	case <-time.After(time.Second):
+		as.Fail("group timeout did not fire after resume")
+	}
+}
+
+func TestDeadlineTimerPause(t *testing.T) {
+	as := assert.New(t)
+
+	ctx, dt := withDeadlineTimer(nil, 40*time.Millisecond, time.Second)
+	defer dt.stop()
+	dt.start()
+	dt.pause()
+	as.Equal(10*time.Millisecond, dt.extend(10*time.Millisecond)) // 暂停中延长加到剩余时长上
+	time.Sleep(60 * time.Millisecond)
+	as.NoError(ctx.Err())
+
+	start := time.Now()
+	dt.resume()
+	<-ctx.Done()
+	as.GreaterOrEqual(time.Since(start), 40*time.Millisecond)
+}
@@ -0,0 +1,124 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// PhasedTask 两阶段任务：所有任务 Prepare 成功后才执行 Commit，否则全部 Rollback
+type PhasedTask interface {
+	Prepare() error
+	Commit() (interface{}, error)
+	Rollback()
+}
+
+// phaseValue 阶段任务的返回值，带上任务下标
+type phaseValue struct {
+	index int
+	value interface{}
+}
+
+// ExecutePhased 以两阶段方式执行 tasks（不使用 AddTask 添加的任务），每个阶段都是一次并发执行，
+// 沿用组的等待时长、上下文、日志和 panic 回调，等待时长对每个阶段分别生效
+//
+// 部分失败的语义：
+//   - 任一 Prepare 返回错误、panic 或超时，立即取消其余 Prepare，对全部任务调用 Rollback（包括未准备成功的任务，
+//     Rollback 需能处理未准备或仍在准备中的状态），返回的错误包装 ErrPrepareFailed 和各 Prepare 错误
+//   - 全部 Prepare 成功后执行 Commit，Commit 阶段不再回滚，results 按任务下标排列，
+//     Commit 失败或 panic 的任务 Error 为对应错误，超时的任务 Status 为 StatusTimedOut
+func (tg *Group) ExecutePhased(tasks ...PhasedTask) (results []Result, err error) {
+	if len(tasks) == 0 {
+		tg.mu.Lock()
+		allowEmpty := tg.allowEmpty
+		tg.mu.Unlock()
+		if allowEmpty {
+			return nil, nil
+		}
+		return nil, tg.configError(ErrNoTasks)
+	}
+
+	prepare := tg.phase("prepare", func(i int) interface{} { return i })
+	for i, t := range tasks {
+		prepare.AddTaskFunc(func() (interface{}, error) {
+			return i, t.Prepare()
+		})
+	}
+	var errs []error
+	prepared, _ := prepare.ExecuteUntil(func(results []Result) bool {
+		last := results[len(results)-1]
+		if last.Error != nil {
+			errs = append(errs, last.Error)
+		}
+		return last.Error != nil
+	})
+	if len(errs) > 0 || len(prepared) < len(tasks) {
+		tg.rollback(tasks)
+		if len(errs) == 0 {
+			errs = append(errs, fmt.Errorf("%d task(s) did not prepare", len(tasks)-len(prepared)))
+		}
+		return nil, fmt.Errorf("%w: %w", ErrPrepareFailed, errors.Join(errs...))
+	}
+
+	commit := tg.phase("commit", func(i int) interface{} { return phaseValue{index: i} })
+	for i, t := range tasks {
+		commit.AddTaskFunc(func() (interface{}, error) {
+			v, err := t.Commit()
+			return phaseValue{index: i, value: v}, err
+		})
+	}
+	committed, _ := commit.ExecuteUntil(func([]Result) bool { return false })
+
+	results = make([]Result, len(tasks))
+	for i := range results {
+		results[i] = Result{Error: context.DeadlineExceeded, Status: StatusTimedOut}
+	}
+	for _, r := range committed {
+		pv := r.Value.(phaseValue)
+		r.Value = pv.value
+		results[pv.index] = r
+	}
+	return results, nil
+}
+
+// rollback 并发回滚全部任务并等待结束（或超时）
+func (tg *Group) rollback(tasks []PhasedTask) {
+	rollback := tg.phase("rollback", nil)
+	for _, t := range tasks {
+		rollback.AddTaskFunc(func() (interface{}, error) {
+			t.Rollback()
+			return nil, nil
+		})
+	}
+	_, _ = rollback.ExecuteUntil(func([]Result) bool { return false })
+}
+
+// phase 创建执行单个阶段的任务组，沿用本组的配置
+// value 不为 nil 时 panic 的任务照常输出日志，并以 value(i) 为值、*PanicError 为错误交付结果
+func (tg *Group) phase(name string, value func(i int) interface{}) *Group {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+
+	name = fmt.Sprintf("%s_%s", tg.name, name)
+	opts := []Option{
+		WithDuration(tg.timeout),
+		WithCtx(tg.ctx),
+		WithLog(tg.log),
+		WithLogSampling(tg.logSampling),
+		WithOnPanic(tg.onPanic),
+		WithLogContextExtractor(tg.logContextExtractor),
+	}
+	if value != nil {
+		log := tg.log
+		opts = append(opts, WithRecoverer(func(i int, recovered interface{}, stack []byte) Result {
+			panicErr := &PanicError{Value: recovered, Stack: stack}
+			logError(log, "task run error", panicErr, map[string]interface{}{
+				"name":  name,
+				"i":     i,
+				"stack": string(stack),
+			})
+			return Result{Value: value(i), Error: panicErr, Status: StatusPanicked}
+		}))
+	}
+	return NewTaskGroup(name, opts...)
+}
@@ -0,0 +1,102 @@
+package job
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type phasedSt struct {
+	prepareErr error
+	commitErr  error
+	value      interface{}
+	panicAt    string        // "prepare" 或 "commit" 时在该阶段 panic
+	prepareFor time.Duration // 准备耗时
+
+	committed  int32
+	rolledBack int32
+}
+
+func (p *phasedSt) Prepare() error {
+	if p.panicAt == "prepare" {
+		panic("prepare boom")
+	}
+	time.Sleep(p.prepareFor)
+	return p.prepareErr
+}
+
+func (p *phasedSt) Commit() (interface{}, error) {
+	atomic.AddInt32(&p.committed, 1)
+	if p.panicAt == "commit" {
+		panic("commit boom")
+	}
+	return p.value, p.commitErr
+}
+
+func (p *phasedSt) Rollback() {
+	atomic.AddInt32(&p.rolledBack, 1)
+}
+
+func TestExecutePhased(t *testing.T) {
+	as := assert.New(t)
+
+	failed := errors.New("failed")
+	a := &phasedSt{value: "a"}
+	b := &phasedSt{value: "b", commitErr: failed}
+	tg := NewTaskGroup("phased", WithDuration(time.Second))
+	ret, err := tg.ExecutePhased(a, b)
+	as.NoError(err)
+	as.Equal([]Result{{Value: "a"}, {Value: "b", Error: failed, Status: StatusFailed}}, ret)
+	as.Equal(int32(1), a.committed)
+	as.Equal(int32(0), a.rolledBack)
+
+	// 任一准备失败时全部回滚，不提交
+	a, b = &phasedSt{value: "a"}, &phasedSt{prepareErr: failed}
+	ret, err = tg.ExecutePhased(a, b)
+	as.ErrorIs(err, ErrPrepareFailed)
+	as.ErrorIs(err, failed)
+	as.Nil(ret)
+	as.Equal(int32(0), a.committed)
+	as.Equal(int32(1), a.rolledBack)
+	as.Equal(int32(1), b.rolledBack)
+
+	_, err = tg.ExecutePhased()
+	as.ErrorIs(err, ErrNoTasks)
+}
+
+func TestExecutePhasedPanic(t *testing.T) {
+	as := assert.New(t)
+
+	log := &memLog{}
+	tg := NewTaskGroup("phased_panic", WithDuration(time.Second), WithLog(log))
+
+	// Commit panic 的任务结果为 PanicError，而不是超时
+	a, b := &phasedSt{value: "a"}, &phasedSt{panicAt: "commit"}
+	ret, err := tg.ExecutePhased(a, b)
+	as.NoError(err)
+	as.Len(ret, 2)
+	as.Equal(Result{Value: "a"}, ret[0])
+	var pe *PanicError
+	as.ErrorAs(ret[1].Error, &pe)
+	as.Equal("commit boom", pe.Value)
+	as.Equal(StatusPanicked, ret[1].Status)
+	as.Equal(1, log.errCount("task run error"))
+
+	// Prepare panic 立即取消其余准备并全部回滚
+	a, b = &phasedSt{value: "a", prepareFor: 500 * time.Millisecond}, &phasedSt{panicAt: "prepare"}
+	start := time.Now()
+	ret, err = tg.ExecutePhased(a, b)
+	as.Less(time.Since(start), 400*time.Millisecond)
+	as.ErrorIs(err, ErrPrepareFailed)
+	as.ErrorContains(err, "prepare boom")
+	as.ErrorAs(err, &pe)
+	as.Nil(ret)
+	as.Equal(int32(0), a.committed)
+	as.Equal(int32(1), a.rolledBack)
+	as.Equal(int32(1), b.rolledBack)
+
+	time.Sleep(500 * time.Millisecond)
+}
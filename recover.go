@@ -0,0 +1,77 @@
+package job
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// WithRecoverer 自定义任务 panic 时的恢复，fn 的返回值作为任务的结果照常交付和统计，可用于把 panic 转换为业务错误；
+// index 为任务下标，recovered 为 recover() 的原始值。返回的 Status 为 StatusPanicked 时计入 Stats.Panicked，
+// 否则按返回的 Status 统计；返回的 Error 不为 nil 而 Status 为零值（StatusSucceeded）时按 Error 取状态（通常为 StatusFailed）；
+// 设置了兜底值的任务同样按兜底值输出
+// 设置后不再输出默认的 "task run error" 日志，WithOnPanic 照常调用；fn 在任务协程中调用，
+// 其自身的 panic 会被恢复并记录日志 "recoverer error"，任务结果退回默认的 *PanicError
+// 默认（不设置）panic 任务的结果 Error 为 *PanicError、Status 为 StatusPanicked，只在设置了兜底值时交付，并输出日志
+func WithRecoverer(fn func(index int, recovered interface{}, stack []byte) Result) Option {
+	return recovererOption(fn)
+}
+
+type recovererOption func(int, interface{}, []byte) Result
+
+func (r recovererOption) bind(o *options) {
+	o.Recoverer = r
+}
+
+// callRecoverer 调用自定义恢复函数，其自身 panic 时记录日志并返回默认的 panic 结果
+func (ex *execution) callRecoverer(fn func(int, interface{}, []byte) Result, i int, recovered interface{}, stack []byte) (ret Result) {
+	defer func() {
+		if r := recover(); r != nil {
+			ex.logError("recoverer error", &PanicError{Value: r}, map[string]interface{}{
+				"i": i,
+			})
+			ret = Result{Error: &PanicError{Value: recovered, Stack: stack}, Status: StatusPanicked}
+		}
+		if ret.Status == StatusSucceeded && ret.Error != nil {
+			ret.Status = statusOf(ret.Error)
+		}
+		if ret.Status == StatusPanicked {
+			atomic.AddInt64(&ex.panics, 1)
+			if ex.keepErrors && ex.classify(StatusPanicked) == OutcomeFailure && ret.Error != nil {
+				ex.mu.Lock()
+				ex.panicErrs = append(ex.panicErrs, ret.Error)
+				ex.mu.Unlock()
+			}
+		}
+	}()
+	return fn(i, recovered, stack)
+}
+
+// deliverRecovered 交付自定义恢复得到的结果，收集方已结束时丢弃
+func (ex *execution) deliverRecovered(ctx context.Context, t Tasker, i int, ret Result) {
+	if ex.async {
+		ex.keepPending(ex.output(i, ret).Result)
+		return
+	}
+	ex.send(ctx, t, ex.output(i, ret))
+}
+
+// recordPanic 记录任务 i 在截止前 panic 的结果，收集结束时据此区分 panic 与超时的任务；截止后才 panic 的任务仍按超时处理
+func (ex *execution) recordPanic(ctx context.Context, i int, ret Result) {
+	if ctx.Err() != nil {
+		return
+	}
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+	if ex.panicked == nil {
+		ex.panicked = make(map[int]Result)
+	}
+	ex.panicked[i] = ret
+}
+
+// panicResult 返回任务 i panic 的结果，没有 panic 时 ok 为 false
+func (ex *execution) panicResult(i int) (Result, bool) {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+	ret, ok := ex.panicked[i]
+	return ret, ok
+}
@@ -0,0 +1,87 @@
+package job
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecoverer(t *testing.T) {
+	as := assert.New(t)
+
+	domain := errors.New("domain error")
+	log := &memLog{}
+	var onPanic int32
+	tg := NewTaskGroup("recoverer", WithCollectRet(), WithDuration(time.Second), WithLog(log),
+		WithOnPanic(func(int, interface{}, []byte) { atomic.AddInt32(&onPanic, 1) }),
+		WithRecoverer(func(i int, recovered interface{}, stack []byte) Result {
+			if recovered == "fatal" {
+				return Result{Error: fmt.Errorf("task %d: %v", i, recovered), Status: StatusPanicked}
+			}
+			return Result{Error: fmt.Errorf("%w: %v", domain, recovered), Status: StatusFailed}
+		}))
+	tg.AddTaskFunc(func() (interface{}, error) { panic("boom") })
+	tg.AddTaskFunc(func() (interface{}, error) { panic("fatal") })
+
+	grs := <-tg.ExecChan()
+	as.NoError(grs.Error)
+	as.ElementsMatch([]Result{
+		{Error: fmt.Errorf("%w: boom", domain), Status: StatusFailed},
+		{Error: errors.New("task 1: fatal"), Status: StatusPanicked},
+	}, grs.Results)
+	as.Equal(1, grs.Stats.Failed)
+	as.Equal(1, grs.Stats.Panicked)
+	as.Zero(grs.Stats.TimedOut)
+	as.Equal(int32(2), atomic.LoadInt32(&onPanic))
+	as.Zero(log.errCount("task run error"))
+}
+
+func TestRecovererPanics(t *testing.T) {
+	as := assert.New(t)
+
+	log := &memLog{}
+	tg := NewTaskGroup("recoverer_panics", WithCollectRet(), WithDuration(time.Second), WithLog(log),
+		WithRecoverer(func(int, interface{}, []byte) Result { panic("recoverer") }))
+	tg.AddTaskFunc(func() (interface{}, error) { panic("boom") })
+
+	grs := <-tg.ExecChan()
+	as.NoError(grs.Error)
+	as.Len(grs.Results, 1)
+	var pe *PanicError
+	as.ErrorAs(grs.Results[0].Error, &pe)
+	as.Equal("boom", pe.Value)
+	as.Equal(StatusPanicked, grs.Results[0].Status)
+	as.Equal(1, grs.Stats.Panicked)
+	as.Equal(1, log.errCount("recoverer error"))
+}
+
+func TestRecovererStatus(t *testing.T) {
+	as := assert.New(t)
+
+	domain := errors.New("domain error")
+	tg := NewTaskGroup("recoverer_status", WithCollectRet(), WithDuration(time.Second), WithRequireAnySuccess(),
+		WithLog(&memLog{}),
+		WithRecoverer(func(_ int, recovered interface{}, _ []byte) Result {
+			if recovered == "fatal" {
+				return Result{Error: fmt.Errorf("fatal: %w", domain), Status: StatusPanicked}
+			}
+			return Result{Error: domain} // 未设置 Status，按 Error 记为失败
+		}))
+	tg.AddTaskFunc(func() (interface{}, error) { panic("boom") })
+	tg.AddTaskFunc(func() (interface{}, error) { panic("fatal") })
+
+	grs := <-tg.ExecChan()
+	as.ErrorIs(grs.Error, ErrAllFailed)
+	as.ErrorIs(grs.Error, domain)
+	as.ErrorContains(grs.Error, "fatal: domain error")
+	as.Equal(1, strings.Count(grs.Error.Error(), "fatal: domain error"))
+	as.Contains(grs.Results, Result{Error: domain, Status: StatusFailed})
+	as.Zero(grs.Stats.Succeeded)
+	as.Equal(1, grs.Stats.Failed)
+	as.Equal(1, grs.Stats.Panicked)
+}
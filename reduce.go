@@ -0,0 +1,38 @@
+package job
+
+// Reduce 执行任务组的所有任务，以 initial 为初始值对每个已交付的结果调用 f，返回折叠后的值和组的错误
+// f 只在收集协程中按结果交付（完成）顺序依次调用，不会并发，因此并发执行时折叠顺序不确定；
+// 需要确定的顺序时设置 WithSequential，或用 ExecuteFixed 取得按下标排列的结果后自行折叠
+// 本次执行以 f 代替 WithAggregator，结果不额外收集；超时未交付的结果不参与折叠（设置兜底值的除外），
+// 未设置 WithDuration 时等待所有任务结束；出错时返回 initial 或已折叠的值以及错误
+func Reduce[T any](tg *Group, initial T, f func(acc T, r Result) T) (T, error) {
+	tg.mu.Lock()
+	if len(tg.tasks) == 0 {
+		tg.runEarlyDefers()
+		tg.mu.Unlock()
+		if tg.allowEmpty {
+			return initial, nil
+		}
+		return initial, tg.configError(ErrNoTasks)
+	}
+	if err := tg.checkRun(); err != nil {
+		tg.runEarlyDefers()
+		tg.mu.Unlock()
+		return initial, err
+	}
+
+	ex := tg.newExecution(tg.ctx)
+	ex.collect = false
+	ex.acc = initial
+	ex.fold = func(acc interface{}, r Result) interface{} {
+		v, _ := acc.(T) // T 为接口类型时 acc 可能为 nil
+		return f(v, r)
+	}
+	tg.run(ex)
+	tg.mu.Unlock()
+
+	defer ex.cancel()
+	grs := tg.groupResult(ex, tg.collectResults(ex))
+	acc, _ := grs.Aggregate.(T)
+	return acc, grs.Error
+}
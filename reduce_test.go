@@ -0,0 +1,42 @@
+package job
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReduce(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("reduce", WithDuration(time.Second))
+	for i := 1; i <= 4; i++ {
+		tg.AddTaskFunc(func() (interface{}, error) { return i, nil })
+	}
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, errors.New("failed") })
+
+	sum, err := Reduce(tg, 0, func(acc int, r Result) int {
+		if r.Error != nil {
+			return acc
+		}
+		return acc + r.Value.(int)
+	})
+	as.NoError(err)
+	as.Equal(10, sum)
+
+	// 顺序执行时按任务下标折叠
+	seq := NewTaskGroup("reduce_sequential", WithSequential(), WithDuration(time.Second))
+	for _, s := range []string{"a", "b", "c"} {
+		seq.AddTaskFunc(func() (interface{}, error) { return s, nil })
+	}
+	joined, err := Reduce(seq, "", func(acc string, r Result) string { return acc + r.Value.(string) })
+	as.NoError(err)
+	as.Equal("abc", joined)
+
+	empty := NewTaskGroup("reduce_empty")
+	n, err := Reduce(empty, 7, func(acc int, r Result) int { return acc + 1 })
+	as.ErrorIs(err, ErrNoTasks)
+	as.Equal(7, n)
+}
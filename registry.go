@@ -0,0 +1,85 @@
+package job
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// GroupStatus 已注册任务组在调用 Registry 时的状态，供调试、管理页面展示
+type GroupStatus struct {
+	Name     string
+	RunID    string // 最近一次执行的 ID，未设置 WithRunID 时为空
+	Tasks    int    // 最近一次执行的任务数，尚未执行时为已添加的任务数
+	Running  []int  // 正在执行的任务下标
+	Finished int    // 组不再等待的任务数（已交付结果、超时或被取消）
+	Ended    int    // 实际执行结束的任务数
+	Started  bool   // 注册后是否已开始执行
+	Paused   bool
+}
+
+var registry = struct {
+	mu     sync.Mutex
+	groups map[*Group]struct{}
+}{groups: make(map[*Group]struct{})}
+
+// Register 把任务组加入包级注册表，Registry 可以列出它；注册是可选的，未注册的任务组不受影响
+// 注册后开始的执行在所有任务结束时自动从注册表移除，避免泄漏；注册后不再执行的任务组需要调用 Deregister 移除
+func Register(tg *Group) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.groups[tg] = struct{}{}
+}
+
+// Deregister 把任务组从注册表移除，未注册时不做任何事
+func Deregister(tg *Group) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	delete(registry.groups, tg)
+}
+
+// Registry 返回所有已注册任务组当前的状态，按名称排序，可以在任意协程中调用
+func Registry() []GroupStatus {
+	registry.mu.Lock()
+	groups := make([]*Group, 0, len(registry.groups))
+	for tg := range registry.groups {
+		groups = append(groups, tg)
+	}
+	registry.mu.Unlock()
+
+	statuses := make([]GroupStatus, 0, len(groups))
+	for _, tg := range groups {
+		statuses = append(statuses, tg.status())
+	}
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// registered 返回任务组是否已注册
+func registered(tg *Group) bool {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	_, ok := registry.groups[tg]
+	return ok
+}
+
+// status 返回任务组当前的状态，见 GroupStatus
+func (tg *Group) status() GroupStatus {
+	tg.mu.Lock()
+	s := GroupStatus{Name: tg.name, Tasks: len(tg.tasks)}
+	ex := tg.cur
+	if ex != nil && ex.group == tg {
+		s.RunID, s.Tasks, s.Started = ex.runID, ex.total, true
+		s.Finished = int(atomic.LoadInt32(&ex.finished))
+		s.Ended = int(atomic.LoadInt32(&ex.ended))
+	}
+	tg.mu.Unlock()
+
+	if s.Started {
+		s.Running = tg.Running()
+	}
+	s.Paused = tg.Paused()
+	return s
+}
@@ -0,0 +1,47 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	as := assert.New(t)
+
+	release := make(chan struct{})
+	tg := NewTaskGroup("registry_b", WithDuration(time.Second), WithCollectRet(), WithRunID("run-1"))
+	tg.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+	tg.AddTaskFunc(func() (interface{}, error) {
+		<-release
+		return 2, nil
+	})
+	idle := NewTaskGroup("registry_a")
+	Register(tg)
+	Register(idle)
+	defer Deregister(idle)
+
+	statuses := Registry()
+	as.Equal([]GroupStatus{{Name: "registry_a"}, {Name: "registry_b", Tasks: 2}}, statuses)
+
+	done := make(chan []Result)
+	go func() {
+		results, _ := tg.Execute()
+		done <- results
+	}()
+	as.Eventually(func() bool {
+		s := Registry()
+		return len(s) == 2 && s[1].Finished == 1 && len(s[1].Running) == 1
+	}, time.Second, 5*time.Millisecond)
+	s := Registry()[1]
+	as.True(s.Started)
+	as.Equal("run-1", s.RunID)
+	as.Equal([]int{1}, s.Running)
+	as.Equal(1, s.Ended)
+
+	close(release)
+	as.Len(<-done, 2)
+	as.Eventually(func() bool { return len(Registry()) == 1 }, time.Second, 5*time.Millisecond)
+	as.Equal("registry_a", Registry()[0].Name)
+}
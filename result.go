@@ -0,0 +1,141 @@
+package job
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// MergeResults 按参数顺序拼接多个任务组的结果
+func MergeResults(results ...[]Result) []Result {
+	n := 0
+	for _, rs := range results {
+		n += len(rs)
+	}
+
+	merged := make([]Result, 0, n)
+	for _, rs := range results {
+		merged = append(merged, rs...)
+	}
+	return merged
+}
+
+// MergeMaps 合并多个按名称索引的结果
+// 冲突策略：同名结果以参数中靠后的 map 为准（后者覆盖前者），与 Option 的绑定顺序一致
+func MergeMaps(maps ...map[string]Result) map[string]Result {
+	n := 0
+	for _, m := range maps {
+		n += len(m)
+	}
+
+	merged := make(map[string]Result, n)
+	for _, m := range maps {
+		for k, r := range m {
+			merged[k] = r
+		}
+	}
+	return merged
+}
+
+// OrderedFrom 按 order 中的名称顺序把命名结果转换为切片，结果与 order 一一对应
+// m 中不存在的名称对应零值 Result；m 中不在 order 里的结果被忽略，order 中重复的名称重复输出
+func OrderedFrom(m map[string]Result, order []string) []Result {
+	results := make([]Result, len(order))
+	for i, name := range order {
+		results[i] = m[name]
+	}
+	return results
+}
+
+// Assign 将命名结果的值按 mapping（结果名 -> 字段名）写入 dst 指向的结构体
+// mapping 为 nil 时字段名与结果名相同，m 中不存在的结果保持字段不变
+// 以下情况跳过对应字段，全部写完后合并返回错误：
+//   - 结果本身带有错误
+//   - 结构体中没有该字段或字段不可导出
+//   - 值的类型不能直接赋给字段（不做类型转换）
+//
+// 值为 nil 时字段置为零值
+func Assign(dst interface{}, m map[string]Result, mapping map[string]string) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("assign: dst must be a non-nil pointer to struct, got %T", dst)
+	}
+	sv := rv.Elem()
+
+	if mapping == nil {
+		mapping = make(map[string]string, len(m))
+		for name := range m {
+			mapping[name] = name
+		}
+	}
+
+	var errs []error
+	for name, field := range mapping {
+		r, ok := m[name]
+		if !ok {
+			continue
+		}
+		if r.Error != nil {
+			errs = append(errs, fmt.Errorf("assign %s: %w", name, r.Error))
+			continue
+		}
+
+		fv := sv.FieldByName(field)
+		if !fv.IsValid() || !fv.CanSet() {
+			errs = append(errs, fmt.Errorf("assign %s: no settable field %q in %s", name, field, sv.Type()))
+			continue
+		}
+		if r.Value == nil {
+			fv.SetZero()
+			continue
+		}
+
+		vv := reflect.ValueOf(r.Value)
+		if !vv.Type().AssignableTo(fv.Type()) {
+			errs = append(errs, fmt.Errorf("assign %s: type mismatch, %s is not assignable to field %s %s", name, vv.Type(), field, fv.Type()))
+			continue
+		}
+		fv.Set(vv)
+	}
+	return errors.Join(errs...)
+}
+
+// FlattenResults 递归展开值为 []Result 或 GroupResult 的结果（嵌套任务组），返回一维结果
+// 外层结果自身带有错误时保留为一个只含错误的结果，其他值原样保留
+func FlattenResults(results []Result) []Result {
+	flat := make([]Result, 0, len(results))
+	return flatten(flat, results)
+}
+
+func flatten(flat []Result, results []Result) []Result {
+	for _, r := range results {
+		var nested []Result
+		switch v := r.Value.(type) {
+		case []Result:
+			nested = v
+		case GroupResult:
+			nested = v.Results
+			if r.Error == nil {
+				r.Error = v.Error
+			}
+		case *GroupResult:
+			if v == nil {
+				flat = append(flat, r)
+				continue
+			}
+			nested = v.Results
+			if r.Error == nil {
+				r.Error = v.Error
+			}
+		default:
+			flat = append(flat, r)
+			continue
+		}
+
+		if r.Error != nil {
+			flat = append(flat, Result{Error: r.Error})
+		}
+		flat = flatten(flat, nested)
+	}
+	return flat
+}
@@ -0,0 +1,108 @@
+package job
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeResults(t *testing.T) {
+	as := assert.New(t)
+
+	err := errors.New("failed")
+	merged := MergeResults(
+		[]Result{{Value: 1}, {Value: 2}},
+		nil,
+		[]Result{{Error: err}},
+	)
+	as.Equal([]Result{{Value: 1}, {Value: 2}, {Error: err}}, merged)
+	as.Empty(MergeResults())
+}
+
+func TestMergeMaps(t *testing.T) {
+	as := assert.New(t)
+
+	merged := MergeMaps(
+		map[string]Result{"a": {Value: 1}, "b": {Value: 2}},
+		map[string]Result{"b": {Value: 3}, "c": {Value: 4}},
+	)
+	as.Equal(map[string]Result{
+		"a": {Value: 1},
+		"b": {Value: 3}, // 后者覆盖前者
+		"c": {Value: 4},
+	}, merged)
+}
+
+func TestOrderedFrom(t *testing.T) {
+	as := assert.New(t)
+
+	m := map[string]Result{"a": {Value: 1}, "b": {Value: 2}, "extra": {Value: 3}}
+	as.Equal([]Result{{Value: 2}, {}, {Value: 1}, {Value: 2}}, OrderedFrom(m, []string{"b", "missing", "a", "b"}))
+	as.Empty(OrderedFrom(m, nil))
+}
+
+func TestAssign(t *testing.T) {
+	as := assert.New(t)
+
+	type data struct {
+		User   string
+		Orders []int
+		Prefs  map[string]string
+		Count  int
+	}
+
+	failed := errors.New("failed")
+	var d data
+	err := Assign(&d, map[string]Result{
+		"user":   {Value: "tom"},
+		"orders": {Value: []int{1, 2}},
+		"prefs":  {Error: failed},
+		"count":  {Value: "not int"},
+	}, map[string]string{
+		"user":   "User",
+		"orders": "Orders",
+		"prefs":  "Prefs",
+		"count":  "Count",
+		"absent": "User",
+	})
+	as.ErrorIs(err, failed)
+	as.ErrorContains(err, "type mismatch")
+	as.Equal(data{User: "tom", Orders: []int{1, 2}}, d)
+
+	// mapping 为空时字段名与结果名相同
+	var d2 data
+	as.NoError(Assign(&d2, map[string]Result{"User": {Value: "jerry"}}, nil))
+	as.Equal("jerry", d2.User)
+
+	as.Error(Assign(d2, nil, nil))
+}
+
+func TestFlattenResults(t *testing.T) {
+	as := assert.New(t)
+
+	failed := errors.New("failed")
+	flat := FlattenResults([]Result{
+		{Value: 1},
+		{Value: []Result{
+			{Value: 2},
+			{Value: []Result{{Value: 3}, {Error: failed}}},
+		}},
+		{Value: GroupResult{Results: []Result{{Value: 4}}, Error: failed}},
+		{Value: []Result{{Value: 5}}, Error: failed},
+		{Value: "plain"},
+		{Value: (*GroupResult)(nil)},
+	})
+	as.Equal([]Result{
+		{Value: 1},
+		{Value: 2},
+		{Value: 3},
+		{Error: failed},
+		{Error: failed},
+		{Value: 4},
+		{Error: failed},
+		{Value: 5},
+		{Value: "plain"},
+		{Value: (*GroupResult)(nil)},
+	}, flat)
+}
@@ -0,0 +1,31 @@
+package job
+
+// WithResultDedup 按 key 去重收集的结果：同一个 key 只保留最先交付（按完成顺序，即收集方收到的顺序）的结果，
+// 之后的重复结果不进入 Results，计入 Stats.Duplicates；适合多个数据源竞速返回相同数据的场景
+// 与 WithResultFilter 一样，重复结果仍计入其他统计，也照常交给 sink；被过滤的结果不参与去重
+func WithResultDedup(key func(Result) string) Option {
+	return resultDedupOption(key)
+}
+
+type resultDedupOption func(Result) string
+
+func (r resultDedupOption) bind(o *options) {
+	o.ResultDedup = r
+}
+
+// duplicate 返回结果是否与之前收集的结果重复，只在收集协程中调用
+func (ex *execution) duplicate(r Result) bool {
+	if ex.dedupKey == nil {
+		return false
+	}
+	key := ex.dedupKey(r)
+	if _, ok := ex.seenKeys[key]; ok {
+		ex.counted.Duplicates++
+		return true
+	}
+	if ex.seenKeys == nil {
+		ex.seenKeys = make(map[string]struct{})
+	}
+	ex.seenKeys[key] = struct{}{}
+	return false
+}
@@ -0,0 +1,28 @@
+package job
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultDedup(t *testing.T) {
+	as := assert.New(t)
+
+	var sunk int
+	tg := NewTaskGroup("result_dedup", WithDuration(time.Second), WithCollectRet(), WithSequential(),
+		WithSink(func(Result) { sunk++ }),
+		WithResultDedup(func(r Result) string { return fmt.Sprint(r.Value) }))
+	for _, v := range []string{"a", "b", "a", "c", "b"} {
+		tg.AddTaskFunc(func() (interface{}, error) { return v, nil })
+	}
+
+	grs := <-tg.ExecChan()
+	as.NoError(grs.Error)
+	as.Equal([]Result{{Value: "a"}, {Value: "b"}, {Value: "c"}}, grs.Results)
+	as.Equal(2, grs.Stats.Duplicates)
+	as.Equal(5, grs.Stats.Succeeded)
+	as.Equal(5, sunk)
+}
@@ -0,0 +1,82 @@
+package job
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// WithRetries 任务返回错误后最多重试 n 次，每次重试都经过中间件；panic、上下文结束后不再重试
+// 所有尝试共用任务的上下文和执行空位，结果为最后一次尝试的结果
+// WithDuration 限制的是包括所有重试和退避等待在内的整个执行：到时后正在退避的任务立即放弃剩余重试，
+// 以最后一次尝试的结果走超时处理，不会因为重试超出等待时长
+func WithRetries(n int) Option {
+	return retriesOption(n)
+}
+
+// WithRetryBackoff 设置两次尝试之间的等待时长，默认立即重试；等待期间任务上下文结束时放弃重试
+func WithRetryBackoff(d time.Duration) Option {
+	return retryBackoffOption(d)
+}
+
+type retryBackoffOption time.Duration
+
+func (r retryBackoffOption) bind(o *options) {
+	o.RetryBackoff = time.Duration(r)
+}
+
+type retriesOption int
+
+func (r retriesOption) bind(o *options) {
+	o.Retries = int(r)
+}
+
+// WithRetryBudget 限制一次执行中所有任务累计的重试次数，防止大量任务同时失败时重试放大下游压力
+// 预算用完后其余失败不再重试，直接返回最后一次的错误；n < 0 表示不限制（默认），n = 0 表示不重试
+// 预算在每次执行开始时重置，计数为原子操作，并发任务之间不会超出预算
+func WithRetryBudget(n int) Option {
+	return retryBudgetOption(n)
+}
+
+type retryBudgetOption int
+
+func (r retryBudgetOption) bind(o *options) {
+	o.RetryBudget = int(r)
+}
+
+// retry 第 attempt 次尝试返回 err 后是否需要重试，需要时从预算中扣除一次并等待退避时长
+func (ex *execution) retry(ctx context.Context, err error, attempt int) bool {
+	if err == nil || attempt >= ex.retries || ctx.Err() != nil {
+		return false
+	}
+	if !ex.takeRetry() {
+		return false
+	}
+	if ex.retryBackoff <= 0 {
+		return true
+	}
+	timer := time.NewTimer(ex.retryBackoff)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false // 退避期间到时，放弃剩余重试
+	}
+}
+
+// takeRetry 从整组的重试预算中取走一次，预算用完时返回 false
+func (ex *execution) takeRetry() bool {
+	for {
+		n := atomic.LoadInt64(&ex.retryBudget)
+		if n < 0 {
+			return true
+		}
+		if n == 0 {
+			return false
+		}
+		if atomic.CompareAndSwapInt64(&ex.retryBudget, n, n-1) {
+			return true
+		}
+	}
+}
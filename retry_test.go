@@ -0,0 +1,91 @@
+package job
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetries(t *testing.T) {
+	as := assert.New(t)
+
+	var attempts int32
+	tg := NewTaskGroup("retries", WithDuration(time.Second), WithCollectRet(), WithRetries(2))
+	tg.AddTaskFunc(func() (interface{}, error) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			return nil, errors.New("flaky")
+		}
+		return "ok", nil
+	})
+
+	results, err := tg.Execute()
+	as.NoError(err)
+	as.Equal([]Result{{Value: "ok", Status: StatusSucceeded}}, results)
+	as.EqualValues(3, atomic.LoadInt32(&attempts))
+}
+
+func TestRetryBudget(t *testing.T) {
+	as := assert.New(t)
+
+	run := func(budget int) int32 {
+		var attempts int32
+		tg := NewTaskGroup("retry_budget", WithDuration(time.Second), WithRetries(3), WithRetryBudget(budget))
+		for i := 0; i < 5; i++ {
+			tg.AddTaskFunc(func() (interface{}, error) {
+				atomic.AddInt32(&attempts, 1)
+				return nil, errors.New("down")
+			})
+		}
+		_, err := tg.Execute()
+		as.NoError(err)
+		return atomic.LoadInt32(&attempts)
+	}
+
+	as.EqualValues(5+4, run(4))  // 所有任务累计最多重试 4 次
+	as.EqualValues(5, run(0))    // 不重试
+	as.EqualValues(5*4, run(-1)) // 不限制
+}
+
+func TestRetryStopsAtDeadline(t *testing.T) {
+	as := assert.New(t)
+
+	down := errors.New("down")
+	var attempts int32
+	handled := make(chan error, 1)
+	task := &retrySt{attempts: &attempts, err: down, handled: handled}
+	tg := NewTaskGroup("retry_deadline", WithDuration(50*time.Millisecond), WithCollectRet(),
+		WithRetries(100), WithRetryBackoff(20*time.Millisecond))
+	tg.AddTask(task)
+
+	start := time.Now()
+	results, err := tg.Execute()
+	as.NoError(err)
+	as.Empty(results)
+	as.Less(time.Since(start), 200*time.Millisecond)
+
+	// 退避期间到时，以最后一次尝试的错误走超时处理
+	as.ErrorIs(<-handled, down)
+	n := atomic.LoadInt32(&attempts)
+	as.LessOrEqual(n, int32(4))
+	time.Sleep(50 * time.Millisecond)
+	as.Equal(n, atomic.LoadInt32(&attempts)) // 到时后不再重试
+}
+
+// retrySt 每次执行都返回 err，记录尝试次数和超时处理
+type retrySt struct {
+	attempts *int32
+	err      error
+	handled  chan error
+}
+
+func (s *retrySt) Execute() (interface{}, error) {
+	atomic.AddInt32(s.attempts, 1)
+	return nil, s.err
+}
+
+func (s *retrySt) TimeoutHandler(ret interface{}, err error) {
+	s.handled <- err
+}
@@ -0,0 +1,63 @@
+package job
+
+// Runner 决定任务在哪个协程中执行，用于接入协程池或自定义调度器
+// Go 必须保证 fn 最终被执行恰好一次，否则组会一直等待该任务直到超时
+type Runner interface {
+	Go(fn func())
+}
+
+// RunnerFunc 函数形式的 Runner
+type RunnerFunc func(fn func())
+
+// Go 调用 f 提交 fn
+func (f RunnerFunc) Go(fn func()) {
+	f(fn)
+}
+
+// goRunner 默认的 Runner，每个任务一个新协程
+type goRunner struct{}
+
+func (goRunner) Go(fn func()) {
+	go fn()
+}
+
+// WithRunner 通过 r 提交任务代替直接 go 启动，如 ants 等协程池；任务的 panic 恢复和计数不受影响
+// r.Go 在持有组锁时调用，阻塞会推迟 Execute 等方法返回
+func WithRunner(r Runner) Option {
+	return runnerOption{r}
+}
+
+type runnerOption struct {
+	Runner
+}
+
+func (r runnerOption) bind(o *options) {
+	o.Runner = r.Runner
+}
+
+// spawn 通过配置的 Runner 提交 fn，同步执行时直接在当前协程中执行
+func (tg *Group) spawn(fn func()) {
+	if tg.synchronous {
+		fn()
+		return
+	}
+	r := tg.runner
+	if r == nil {
+		r = goRunner{}
+	}
+	r.Go(fn)
+}
+
+// WithSynchronous 测试模式：在调用 Execute 等方法的协程中按添加顺序逐个执行任务，不启动任务协程，
+// 结果、错误、收集和超时处理的语义不变，结果按添加顺序到达，便于写出确定的单元测试；仅用于测试，不要在生产中使用
+// 任务在持有组锁时执行，任务中不能调用组的方法；等待时长在任务执行期间照常计时，到时后剩余任务直接走超时处理；
+// 依赖任务必须排在其依赖之后，WithStartGate、Pause 会阻塞调用方；设置后忽略 WithRunner、WithResultChanSize 和 WithCostOrdering
+func WithSynchronous() Option {
+	return synchronousOption(true)
+}
+
+type synchronousOption bool
+
+func (s synchronousOption) bind(o *options) {
+	o.Synchronous = bool(s)
+}
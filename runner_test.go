@@ -0,0 +1,115 @@
+package job
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// pool 固定数量工作协程的简单协程池
+type pool struct {
+	jobs chan func()
+	wg   sync.WaitGroup
+}
+
+func newPool(n int) *pool {
+	p := &pool{jobs: make(chan func())}
+	for i := 0; i < n; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			for fn := range p.jobs {
+				fn()
+			}
+		}()
+	}
+	return p
+}
+
+func (p *pool) Go(fn func()) {
+	go func() { p.jobs <- fn }() // 不阻塞提交方
+}
+
+func (p *pool) close() {
+	close(p.jobs)
+	p.wg.Wait()
+}
+
+func TestRunner(t *testing.T) {
+	as := assert.New(t)
+
+	p := newPool(2)
+	defer p.close()
+
+	log := &memLog{}
+	tg := NewTaskGroup("runner", WithDuration(time.Second), WithRunner(p), WithCollectRet(), WithLog(log))
+	for i := 0; i < 5; i++ {
+		tg.AddTaskFunc(func() (interface{}, error) { return i, nil })
+	}
+	tg.AddTaskFunc(func() (interface{}, error) { panic("boom") })
+
+	results, err := tg.Execute()
+	as.NoError(err)
+	as.Len(results, 5) // panic 的任务在池协程中被恢复，不产生结果
+	var values []interface{}
+	for _, r := range results {
+		values = append(values, r.Value)
+	}
+	as.ElementsMatch([]interface{}{0, 1, 2, 3, 4}, values)
+	as.Equal(1, log.errCount("task run error"))
+}
+
+func TestRunnerFunc(t *testing.T) {
+	as := assert.New(t)
+
+	var submitted int32
+	r := RunnerFunc(func(fn func()) {
+		atomic.AddInt32(&submitted, 1)
+		go fn()
+	})
+
+	tg := NewTaskGroup("runner_func", WithDuration(time.Second), WithRunner(r))
+	tg.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+	tg.AddTaskFunc(func() (interface{}, error) { return 2, nil })
+	_, err := tg.Execute()
+	as.NoError(err)
+	as.EqualValues(2, atomic.LoadInt32(&submitted))
+
+	// 顺序执行只提交一次
+	atomic.StoreInt32(&submitted, 0)
+	seq := NewTaskGroup("runner_seq", WithDuration(time.Second), WithRunner(r), WithSequential())
+	seq.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+	seq.AddTaskFunc(func() (interface{}, error) { return 2, nil })
+	_, err = seq.Execute()
+	as.NoError(err)
+	as.EqualValues(1, atomic.LoadInt32(&submitted))
+}
+
+func TestSynchronous(t *testing.T) {
+	as := assert.New(t)
+
+	var order []int
+	tg := NewTaskGroup("sync", WithDuration(50*time.Millisecond), WithCollectRet(), WithSynchronous(),
+		WithResultChanSize(0))
+	for i := 0; i < 5; i++ {
+		tg.AddTaskFunc(func() (interface{}, error) {
+			order = append(order, i) // 在调用方协程中执行，无需加锁
+			return i, nil
+		})
+	}
+	slow := &slowSt{d: 80 * time.Millisecond, value: 5, handled: make(chan error, 1)}
+	tg.AddTask(slow)
+	skipped := &slowSt{value: 6, handled: make(chan error, 1)}
+	tg.AddTask(skipped)
+
+	results, err := tg.Execute()
+	as.NoError(err)
+	as.Equal([]int{0, 1, 2, 3, 4}, order)
+	as.Equal([]Result{{Value: 0}, {Value: 1}, {Value: 2}, {Value: 3}, {Value: 4}}, results) // 结果按添加顺序
+	as.NoError(<-slow.handled)                                                              // 执行完时已超时，走超时处理
+	as.ErrorIs(<-skipped.handled, context.DeadlineExceeded)                                 // 到时后剩余任务不再执行
+}
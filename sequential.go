@@ -0,0 +1,17 @@
+package job
+
+import "context"
+
+// WithSequential 按添加顺序逐个执行任务，前一个任务结束后才开始下一个，等待时长对整组生效
+// 每个任务开始前检查其上下文，组超时、被取消或任务被 CancelTask 取消后不再执行剩余任务，
+// 剩余任务直接走超时处理（ret 为 nil，err 为取消原因）；依赖任务必须排在其依赖之后
+func WithSequential() Option {
+	return sequentialOption(true)
+}
+
+// runSequential 在单个协程中依次执行任务，上下文已结束的任务不再执行，直接走超时处理
+func (tg *Group) runSequential(ex *execution, tasks []Tasker) {
+	for i, task := range tasks {
+		tg.runTask(ex, ex.ctxs[i], task, i, context.Cause(ex.ctxs[i]))
+	}
+}
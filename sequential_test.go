@@ -0,0 +1,105 @@
+package job
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSequential(t *testing.T) {
+	as := assert.New(t)
+
+	var mu sync.Mutex
+	var order []int
+	tg := NewTaskGroup("sequential", WithCollectRet(), WithDuration(time.Second), WithSequential())
+	for i := 0; i < 3; i++ {
+		tg.AddTaskFunc(func() (interface{}, error) {
+			time.Sleep(time.Duration(3-i) * 5 * time.Millisecond)
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+			return i, nil
+		})
+	}
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Equal([]Result{{Value: 0}, {Value: 1}, {Value: 2}}, ret)
+	as.Equal([]int{0, 1, 2}, order)
+}
+
+// TestSequentialCancel 中途取消后剩余任务不再执行，走超时处理
+func TestSequentialCancel(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("sequential_cancel", WithCollectRet(), WithDuration(time.Second), WithSequential())
+	first := newTimeoutSt("first", 0)
+	slow := newTimeoutSt("slow", 30*time.Millisecond)
+	rest := newTimeoutSt("rest", 0)
+	tg.AddTask(first)
+	tg.AddTask(slow)
+	tg.AddTask(rest)
+
+	ch, cancel := tg.ExecChanWithCancel()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	grs := <-ch
+	as.NoError(grs.Error)
+	as.Equal([]Result{{Value: "first"}}, grs.Results)
+	as.Equal("slow", <-slow.timedOut)
+	as.Nil(<-rest.timedOut) // 未开始执行
+	as.Empty(first.timedOut)
+}
+
+// TestSequentialSkippedBookkeeping 取消后未执行的任务同样发布依赖结果、回调指标并报告错误
+func TestSequentialSkippedBookkeeping(t *testing.T) {
+	as := assert.New(t)
+
+	var mu sync.Mutex
+	var metrics []TaskMetric
+	tg := NewTaskGroup("sequential_skipped", WithCollectRet(), WithDuration(time.Second), WithSequential(),
+		WithMetricsHook(func(m TaskMetric) {
+			mu.Lock()
+			metrics = append(metrics, m)
+			mu.Unlock()
+		}))
+	tg.AddTask(newTestSt("slow", 30*time.Millisecond, true))
+	tg.AddNamedTask("a", newTestSt("a", 0, true))
+	tg.AddDependentTask("after", []string{"a"}, func(map[string]Result) Tasker {
+		panic("should not be called")
+	})
+
+	ch := tg.ExecChan()
+	time.Sleep(10 * time.Millisecond)
+	as.True(tg.CancelTask(1)) // "a" 还没开始
+
+	start := time.Now()
+	grs := <-ch
+	as.Less(time.Since(start), 500*time.Millisecond) // 依赖任务不等到组截止
+	as.NoError(grs.Error)
+	as.Len(grs.Results, 2)
+	as.Equal(Result{Value: "slow"}, grs.Results[0])
+	as.ErrorIs(grs.Results[1].Error, ErrDependencyFailed)
+	as.Equal([]int{1, 2}, grs.NotStarted) // 被跳过的依赖任务同样没有开始执行
+
+	mu.Lock()
+	as.Len(metrics, 3)
+	as.Equal(StatusTimedOut, metrics[1].Status)
+	as.True(metrics[1].TimedOut)
+	mu.Unlock()
+
+	// RunInErrGroup 报告未执行任务的取消原因
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	eg := &fakeErrGroup{}
+	tg = NewTaskGroup("sequential_errgroup", WithCtx(ctx), WithDuration(time.Second), WithSequential())
+	tg.AddTaskFunc(func() (interface{}, error) {
+		cancel()
+		return nil, nil
+	})
+	tg.AddTask(newTestSt("rest", 0, true))
+	as.NoError(tg.RunInErrGroup(eg))
+	as.ErrorIs(eg.Wait(), context.Canceled)
+}
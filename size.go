@@ -0,0 +1,72 @@
+package job
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
+// Sizer 任务结果的值实现它时 WithMaxResultBytes 以 Size() 作为值的字节数
+type Sizer interface {
+	Size() int
+}
+
+// WithMaxResultBytes 成功结果的值超过 n 字节时丢弃该值，结果改为失败，错误包装 ErrResultTooLarge，
+// 丢弃的字节数累计在 Stats.DroppedBytes；值的大小优先取 Sizer，string、[]byte 取长度，
+// 其他值用反射粗略估算：切片、数组、map 按元素个数乘元素类型大小，指针取指向的值，不递归统计元素引用的内存；
+// 兜底值（AddTaskWithDefault、WithDefaultOnTimeout）不受限制；n <= 0 表示不限制（默认）
+func WithMaxResultBytes(n int) Option {
+	return maxResultBytesOption(n)
+}
+
+type maxResultBytesOption int
+
+func (m maxResultBytesOption) bind(o *options) {
+	o.MaxResultBytes = int(m)
+}
+
+// sizeOf 返回值 v 的字节数，见 WithMaxResultBytes
+func sizeOf(v interface{}) int {
+	switch v := v.(type) {
+	case nil:
+		return 0
+	case Sizer:
+		return v.Size()
+	case string:
+		return len(v)
+	case []byte:
+		return len(v)
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return 0
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.Len()
+	case reflect.Slice, reflect.Array:
+		return rv.Len() * int(rv.Type().Elem().Size())
+	case reflect.Map:
+		return rv.Len() * int(rv.Type().Key().Size()+rv.Type().Elem().Size())
+	}
+	return int(rv.Type().Size())
+}
+
+// limitSize 成功结果的值超过 WithMaxResultBytes 时替换为错误
+func (ex *execution) limitSize(ret Result) Result {
+	if ex.maxBytes <= 0 || ret.Error != nil {
+		return ret
+	}
+	n := sizeOf(ret.Value)
+	if n <= ex.maxBytes {
+		return ret
+	}
+	atomic.AddInt64(&ex.dropped, int64(n))
+	return Result{
+		Error:  fmt.Errorf("%w: %d bytes exceeds %d", ErrResultTooLarge, n, ex.maxBytes),
+		Status: StatusFailed,
+	}
+}
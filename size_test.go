@@ -0,0 +1,51 @@
+package job
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type blob struct{ n int }
+
+func (b blob) Size() int { return b.n }
+
+func TestMaxResultBytes(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("max_result_bytes", WithDuration(time.Second), WithCollectRet(), WithMaxResultBytes(8))
+	tg.AddTaskFunc(func() (interface{}, error) { return "small", nil })
+	tg.AddTaskFunc(func() (interface{}, error) { return make([]byte, 20), nil })
+	tg.AddTaskFunc(func() (interface{}, error) { return blob{n: 100}, nil })
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, errors.New("failed") })
+
+	grs := <-tg.ExecChan()
+	as.NoError(grs.Error)
+	as.Len(grs.Results, 4)
+	tooLarge := 0
+	for _, r := range grs.Results {
+		if errors.Is(r.Error, ErrResultTooLarge) {
+			tooLarge++
+			as.Nil(r.Value)
+			as.Equal(StatusFailed, r.Status)
+		} else if r.Error == nil {
+			as.Equal("small", r.Value)
+		}
+	}
+	as.Equal(2, tooLarge)
+	as.Equal(int64(120), grs.Stats.DroppedBytes)
+	as.Equal(3, grs.Stats.Failed)
+}
+
+func TestSizeOf(t *testing.T) {
+	as := assert.New(t)
+
+	as.Equal(0, sizeOf(nil))
+	as.Equal(3, sizeOf("abc"))
+	as.Equal(40, sizeOf(make([]int64, 5)))
+	as.Equal(16, sizeOf(&[2]int64{}))
+	as.Equal(8, sizeOf(int64(1)))
+	as.Equal(0, sizeOf((*int)(nil)))
+}
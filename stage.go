@@ -0,0 +1,134 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync/atomic"
+)
+
+// AddStagedTask 添加属于阶段 stage 的任务，结果的 Stage 为 stage；其他方式添加的任务属于阶段 0
+// 设置 WithStagedExecution 时按阶段从小到大依次执行，同一阶段内的任务并发执行
+func (tg *Group) AddStagedTask(stage int, t Tasker) {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+
+	tg.stages = setIndex(tg.stages, len(tg.tasks), stage)
+	tg.tasks = append(tg.tasks, t)
+}
+
+// WithStagedExecution 按阶段依次执行：前面阶段的任务全部结束（完成、失败或超时）后才开始下一阶段，
+// 阶段内的任务并发执行，等待时长对整组生效；比依赖任务更轻量，适合校验、拉取、写入这类粗粒度的先后关系
+// 依赖任务不能依赖后面阶段的任务；顺序执行（WithSequential）、同步执行（WithSynchronous）时本就按添加顺序执行，不再按阶段排序
+func WithStagedExecution() Option {
+	return stagedExecutionOption(true)
+}
+
+type stagedExecutionOption bool
+
+func (s stagedExecutionOption) bind(o *options) {
+	o.StagedExecution = bool(s)
+}
+
+// WithStageFailFast 分阶段执行时，某个阶段有任务失败（返回错误、panic）后不再执行后面的阶段，
+// 这些任务的错误同时包装 ErrStageFailed 和 ErrSkipped，结果状态为跳过
+func WithStageFailFast() Option {
+	return stageFailFastOption(true)
+}
+
+type stageFailFastOption bool
+
+func (s stageFailFastOption) bind(o *options) {
+	o.StageFailFast = bool(s)
+}
+
+// stagePlan 一次执行的阶段划分，按阶段序号（阶段从小到大的位置）记录进度
+type stagePlan struct {
+	stage    []int           // 任务下标 -> 阶段
+	ord      []int           // 任务下标 -> 阶段序号
+	values   []int           // 阶段序号 -> 阶段
+	left     []int32         // 阶段序号 -> 尚未结束的任务数，原子读写
+	done     []chan struct{} // 阶段序号 -> 阶段内任务全部结束后关闭
+	failed   []int32         // 阶段序号 -> 阶段内有任务失败，原子读写
+	ordered  bool            // 按阶段依次执行
+	failFast bool
+}
+
+// newStages 为本次执行建立阶段划分，没有分阶段任务时不建立，调用方需持有 tg.mu
+func (tg *Group) newStages(ex *execution) {
+	if len(tg.stages) == 0 {
+		return
+	}
+	p := &stagePlan{
+		stage:    make([]int, len(tg.tasks)),
+		ord:      make([]int, len(tg.tasks)),
+		ordered:  tg.stagedExecution && !tg.sequential && !tg.synchronous,
+		failFast: tg.stageFailFast,
+	}
+	seen := make(map[int]bool)
+	for i := range tg.tasks {
+		p.stage[i] = tg.stages[i]
+		if !seen[p.stage[i]] {
+			seen[p.stage[i]] = true
+			p.values = append(p.values, p.stage[i])
+		}
+	}
+	sort.Ints(p.values)
+	ords := make(map[int]int, len(p.values))
+	for ord, stage := range p.values {
+		ords[stage] = ord
+	}
+	p.left = make([]int32, len(p.values))
+	p.failed = make([]int32, len(p.values))
+	p.done = make([]chan struct{}, len(p.values))
+	for ord := range p.done {
+		p.done[ord] = make(chan struct{})
+	}
+	for i, stage := range p.stage {
+		p.ord[i] = ords[stage]
+		p.left[p.ord[i]]++
+	}
+	ex.stages = p
+}
+
+// of 任务 i 的阶段，p 为 nil 时为 0
+func (p *stagePlan) of(i int) int {
+	if p == nil {
+		return 0
+	}
+	return p.stage[i]
+}
+
+// wait 按阶段执行时等待前一阶段结束，前面阶段失败且设置了 WithStageFailFast 时返回跳过的错误
+func (p *stagePlan) wait(ctx context.Context, i int) error {
+	if p == nil || !p.ordered || p.ord[i] == 0 {
+		return nil
+	}
+	select {
+	case <-p.done[p.ord[i]-1]: // 前一阶段的任务都等过更前面的阶段
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if err := ctx.Err(); err != nil {
+		return err // 前面的阶段因截止或取消提前结束
+	}
+	if p.failFast {
+		for ord := 0; ord < p.ord[i]; ord++ {
+			if atomic.LoadInt32(&p.failed[ord]) == 1 {
+				return fmt.Errorf("stage %d: %w: %w", p.values[ord], ErrStageFailed, ErrSkipped)
+			}
+		}
+	}
+	return nil
+}
+
+// end 任务 i 结束时调用，记录失败，阶段内最后一个任务结束时放行下一阶段
+func (p *stagePlan) end(i int, ret Result) {
+	ord := p.ord[i]
+	if ret.Error != nil && ret.Status != StatusSkipped {
+		atomic.StoreInt32(&p.failed[ord], 1)
+	}
+	if atomic.AddInt32(&p.left[ord], -1) == 0 {
+		close(p.done[ord])
+	}
+}
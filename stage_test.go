@@ -0,0 +1,71 @@
+package job
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStagedExecution(t *testing.T) {
+	as := assert.New(t)
+
+	var mu sync.Mutex
+	var order []int
+	record := func(stage int, d time.Duration) TaskFunc {
+		return func() (interface{}, error) {
+			time.Sleep(d)
+			mu.Lock()
+			order = append(order, stage)
+			mu.Unlock()
+			return stage, nil
+		}
+	}
+
+	tg := NewTaskGroup("staged", WithDuration(time.Second), WithCollectRet(), WithStagedExecution())
+	tg.AddStagedTask(2, record(2, 0))
+	tg.AddStagedTask(1, record(1, 20*time.Millisecond))
+	tg.AddStagedTask(1, record(1, 10*time.Millisecond))
+	tg.AddTask(record(0, 30*time.Millisecond)) // 未指定阶段属于阶段 0
+	tg.AddStagedTask(5, record(5, 0))
+
+	results, err := tg.Execute()
+	as.NoError(err)
+	as.Equal([]int{0, 1, 1, 2, 5}, order)
+	if as.Len(results, 5) {
+		for _, r := range results {
+			as.Equal(r.Value, r.Stage) // 结果记录阶段
+		}
+	}
+}
+
+func TestStageFailFast(t *testing.T) {
+	as := assert.New(t)
+
+	failed := errors.New("invalid")
+	run := func(opts ...Option) []Result {
+		tg := NewTaskGroup("stage_fail_fast", append(opts, WithDuration(time.Second), WithCollectRet(),
+			WithStagedExecution())...)
+		tg.AddStagedTask(1, TaskFunc(func() (interface{}, error) { return nil, failed }))
+		tg.AddStagedTask(1, TaskFunc(func() (interface{}, error) { return "ok", nil }))
+		tg.AddStagedTask(2, TaskFunc(func() (interface{}, error) { return "fetched", nil }))
+		tg.AddStagedTask(3, TaskFunc(func() (interface{}, error) { return "written", nil }))
+		results, err := tg.Execute()
+		as.NoError(err)
+		return results
+	}
+
+	stats := func(results []Result) (skipped int) {
+		for _, r := range results {
+			if r.Status == StatusSkipped {
+				as.ErrorIs(r.Error, ErrStageFailed)
+				skipped++
+			}
+		}
+		return skipped
+	}
+	as.Equal(2, stats(run(WithStageFailFast()))) // 阶段 2、3 被跳过
+	as.Equal(0, stats(run()))                    // 默认失败后仍执行后面的阶段
+}
@@ -0,0 +1,58 @@
+package job
+
+import (
+	"context"
+	"time"
+)
+
+// WithSendStallTimeout 任务发送结果被阻塞超过 d 时记录日志 "result send stalled"（带任务下标、标签和已等待时长），
+// 之后每隔 d 再记录一次，直到发送成功或任务上下文结束；只用于发现卡住的消费方（如 WithSink、ExecuteJSON 的写入），
+// 不会丢弃结果，结果通道有缓存时（默认）只有缓存写满才会阻塞；d <= 0 表示不检测（默认）
+func WithSendStallTimeout(d time.Duration) Option {
+	return sendStallTimeoutOption(d)
+}
+
+type sendStallTimeoutOption time.Duration
+
+func (s sendStallTimeoutOption) bind(o *options) {
+	o.SendStallTimeout = time.Duration(s)
+}
+
+// send 把任务 t 的结果发送给收集方，ctx 先结束时返回 false
+func (ex *execution) send(ctx context.Context, t Tasker, tr taskResult) bool {
+	if ex.sendStall <= 0 {
+		select {
+		case ex.retChan <- tr:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	select {
+	case ex.retChan <- tr:
+		return true
+	default:
+	}
+	start := time.Now()
+	timer := time.NewTimer(ex.sendStall)
+	defer timer.Stop()
+	for {
+		select {
+		case ex.retChan <- tr:
+			return true
+		case <-ctx.Done():
+			return false
+		case <-timer.C:
+			data := map[string]interface{}{
+				"i":      tr.index,
+				"waited": time.Since(start).String(),
+			}
+			if labels := taskLabels(t); labels != nil {
+				data["labels"] = labels
+			}
+			ex.logInfo("result send stalled", data)
+			timer.Reset(ex.sendStall)
+		}
+	}
+}
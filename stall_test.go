@@ -0,0 +1,39 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendStallTimeout(t *testing.T) {
+	as := assert.New(t)
+
+	log := &memLog{}
+	stalled := make(chan struct{})
+	tg := NewTaskGroup("send_stall", WithDuration(time.Second), WithCollectRet(), WithLog(log),
+		WithResultChanSize(0), WithSendStallTimeout(20*time.Millisecond),
+		WithSink(func(r Result) {
+			if r.Value == 1 {
+				close(stalled)
+				time.Sleep(80 * time.Millisecond) // 消费方卡住
+			}
+		}))
+	tg.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+	tg.AddTask(Labeled(TaskFunc(func() (interface{}, error) {
+		<-stalled
+		return 2, nil
+	}), map[string]string{"kind": "second"}))
+
+	results, err := tg.Execute()
+	as.NoError(err)
+	as.Len(results, 2) // 不丢弃结果
+	as.GreaterOrEqual(log.infoCount("result send stalled"), 1)
+	for i, msg := range log.infos {
+		if msg == "result send stalled" {
+			as.Equal(1, log.infoDat[i]["i"])
+			as.Equal(map[string]string{"kind": "second"}, log.infoDat[i]["labels"])
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package job
+
+import (
+	"fmt"
+	"time"
+)
+
+// WithStallTimeout 收集结果时超过 d 没有任何任务完成（交付结果）就取消任务组，即使等待时长还没到，
+// 用于尽早发现整体卡住的任务；每收到一个结果重新计时，从开始收集时计时
+// 取消后未结束的任务走超时处理，任务上下文的 context.Cause 为 *CancelError（errors.Is(err, ErrStalled) 为 true），
+// GroupResult.CancelReason 为 CanceledByStall，GroupResult.Error 包装 ErrStalled；
+// 只对有收集方的执行生效，异步执行（无等待时长）不检测；d <= 0 表示不检测（默认）
+func WithStallTimeout(d time.Duration) Option {
+	return stallTimeoutOption(d)
+}
+
+type stallTimeoutOption time.Duration
+
+func (s stallTimeoutOption) bind(o *options) {
+	o.StallTimeout = time.Duration(s)
+}
+
+// stallError 因停滞取消时组的错误
+func (tg *Group) stallError() error {
+	return tg.configError(fmt.Errorf("%w: no task completed within %v", ErrStalled, tg.stallTimeout))
+}
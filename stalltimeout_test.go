@@ -0,0 +1,46 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStallTimeout(t *testing.T) {
+	as := assert.New(t)
+
+	cause := make(chan error, 1)
+	log := &memLog{}
+	tg := NewTaskGroup("stall", WithDuration(5*time.Second), WithCollectRet(), WithLog(log),
+		WithStallTimeout(50*time.Millisecond))
+	// 每 30ms 完成一个任务，不触发停滞
+	for i := 1; i <= 3; i++ {
+		tg.AddTaskFunc(func() (interface{}, error) {
+			time.Sleep(time.Duration(i) * 30 * time.Millisecond)
+			return i, nil
+		})
+	}
+	tg.AddTaskFuncCtx(blockingTask(cause))
+
+	start := time.Now()
+	grs := <-tg.ExecChan()
+	as.ErrorIs(grs.Error, ErrStalled)
+	as.Equal(CanceledByStall, grs.CancelReason)
+	as.Len(grs.Results, 3)
+	as.Less(time.Since(start), time.Second)
+	err := <-cause
+	as.ErrorIs(err, ErrStalled)
+	as.ErrorIs(err, context.Canceled)
+	as.Equal(1, log.infoCount("task group stalled"))
+
+	// 没有停滞时不影响结果
+	tg = NewTaskGroup("no_stall", WithDuration(time.Second), WithCollectRet(), WithStallTimeout(50*time.Millisecond))
+	tg.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+	grs = <-tg.ExecChan()
+	as.NoError(grs.Error)
+	as.Equal(NotCanceled, grs.CancelReason)
+	as.False(errors.Is(&CancelError{Reason: CanceledByCaller}, ErrStalled))
+}
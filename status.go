@@ -0,0 +1,109 @@
+package job
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Skip 返回 ErrSkipped，用于在任务中表达“无需执行”：return nil, job.Skip()
+func Skip() error {
+	return ErrSkipped
+}
+
+// TaskStatus 任务结果的状态
+type TaskStatus int
+
+const (
+	StatusSucceeded TaskStatus = iota // 正常完成
+	StatusFailed                      // 返回了错误
+	StatusSkipped                     // 返回了 ErrSkipped（或包装了它的错误）
+	StatusPanicked                    // 执行时 panic
+	StatusTimedOut                    // 超时或被取消前没有交付结果
+)
+
+func (s TaskStatus) String() string {
+	switch s {
+	case StatusSucceeded:
+		return "succeeded"
+	case StatusFailed:
+		return "failed"
+	case StatusSkipped:
+		return "skipped"
+	case StatusPanicked:
+		return "panicked"
+	case StatusTimedOut:
+		return "timed out"
+	default:
+		return "unknown"
+	}
+}
+
+// statusOf 根据任务返回的错误得到状态
+func statusOf(err error) TaskStatus {
+	switch {
+	case err == nil:
+		return StatusSucceeded
+	case errors.Is(err, ErrSkipped):
+		return StatusSkipped
+	default:
+		return StatusFailed
+	}
+}
+
+// Stats 一次执行结束时的统计，跳过的任务不计入失败
+// 异步执行（无等待时长）不等待任务，只有 Total 有意义
+type Stats struct {
+	Total     int
+	Succeeded int
+	Failed    int
+	Skipped   int
+	Panicked  int
+	TimedOut  int // 结束时仍未交付结果的任务（超时、被取消）
+	Duration  time.Duration
+	// DroppedBytes 因超过 WithMaxResultBytes 被丢弃的结果值的字节数
+	DroppedBytes int64
+	// Duplicates 因 WithResultDedup 未收集的重复结果数
+	Duplicates int
+}
+
+// count 统计一个已交付的结果，只在收集协程中调用
+func (s *Stats) count(r Result) {
+	switch r.Status {
+	case StatusSucceeded:
+		s.Succeeded++
+	case StatusFailed:
+		s.Failed++
+	case StatusSkipped:
+		s.Skipped++
+	}
+}
+
+// stats 返回收集结束时的统计
+func (ex *execution) stats() Stats {
+	s := ex.counted
+	s.Total = ex.total
+	s.Panicked = int(atomic.LoadInt64(&ex.panics))
+	s.DroppedBytes = atomic.LoadInt64(&ex.dropped)
+	s.TimedOut = s.Total - s.Succeeded - s.Failed - s.Skipped - s.Panicked
+	if s.TimedOut < 0 {
+		s.TimedOut = 0
+	}
+	s.Duration = time.Since(ex.start)
+	return s
+}
+
+// allFailedError 合并所有归为失败的任务的失败原因
+func (ex *execution) allFailedError(s Stats) error {
+	ex.mu.Lock()
+	errs := make([]error, 0, len(ex.failures)+len(ex.panicErrs)+1)
+	errs = append(errs, ex.failures...)
+	errs = append(errs, ex.panicErrs...)
+	ex.mu.Unlock()
+
+	if s.TimedOut > 0 && ex.classify(StatusTimedOut) == OutcomeFailure {
+		errs = append(errs, fmt.Errorf("%d task(s) timed out", s.TimedOut))
+	}
+	return fmt.Errorf("%w: %w", ErrAllFailed, errors.Join(errs...))
+}
@@ -0,0 +1,156 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkipAndStats(t *testing.T) {
+	as := assert.New(t)
+
+	failed := errors.New("failed")
+	tg := NewTaskGroup("stats", WithCollectRet(), WithDuration(50*time.Millisecond), WithLog(&memLog{}))
+	tg.AddTaskFunc(func() (interface{}, error) { return "ok", nil })
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, failed })
+	tg.AddTaskFuncCtx(func(ctx context.Context) (interface{}, error) {
+		if ctx.Err() == nil {
+			return nil, Skip()
+		}
+		return "ran", nil
+	})
+	tg.AddTaskFunc(func() (interface{}, error) { panic("boom") })
+	tg.AddTask(newTestSt("timeout", 100*time.Millisecond, true))
+
+	grs := <-tg.ExecChan()
+	as.NoError(grs.Error)
+	as.ElementsMatch([]Result{
+		{Value: "ok"},
+		{Error: failed, Status: StatusFailed},
+		{Error: ErrSkipped, Status: StatusSkipped},
+	}, grs.Results)
+
+	as.Equal(5, grs.Stats.Total)
+	as.Equal(1, grs.Stats.Succeeded)
+	as.Equal(1, grs.Stats.Failed)
+	as.Equal(1, grs.Stats.Skipped)
+	as.Equal(1, grs.Stats.Panicked)
+	as.Equal(1, grs.Stats.TimedOut)
+	as.GreaterOrEqual(grs.Stats.Duration, 50*time.Millisecond)
+
+	time.Sleep(100 * time.Millisecond)
+}
+
+func TestRequireAnySuccess(t *testing.T) {
+	as := assert.New(t)
+
+	failed := errors.New("failed")
+	tg := NewTaskGroup("require_any", WithCollectRet(), WithDuration(50*time.Millisecond),
+		WithRequireAnySuccess(), WithLog(&memLog{}))
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, failed })
+	tg.AddTaskFunc(func() (interface{}, error) { panic("boom") })
+	tg.AddTask(newTestSt("timeout", 100*time.Millisecond, true))
+
+	ret, err := tg.Execute()
+	as.ErrorIs(err, ErrAllFailed)
+	as.ErrorIs(err, failed)
+	as.ErrorContains(err, "boom")
+	as.ErrorContains(err, "1 task(s) timed out")
+	as.Equal([]Result{{Error: failed, Status: StatusFailed}}, ret)
+
+	// 任一成功即可
+	tg.Reset()
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, failed })
+	tg.AddTask(newTestSt("normal", 0, true))
+	ret, err = tg.Execute()
+	as.NoError(err)
+	as.Len(ret, 2)
+
+	time.Sleep(100 * time.Millisecond)
+}
+
+func TestResultFilter(t *testing.T) {
+	as := assert.New(t)
+
+	var sunk int
+	tg := NewTaskGroup("filter", WithCollectRet(), WithDuration(time.Second),
+		WithSink(func(Result) { sunk++ }),
+		WithResultFilter(func(r Result) bool { return r.Value != nil }))
+	tg.AddTask(newTestSt("normal", 0, true))
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, nil })
+
+	grs := <-tg.ExecChan()
+	as.NoError(grs.Error)
+	as.Equal([]Result{{Value: "normal"}}, grs.Results)
+	as.Equal(2, grs.Stats.Succeeded) // 被过滤的结果仍计入统计
+	as.Equal(2, sunk)
+}
+
+func TestPanicError(t *testing.T) {
+	as := assert.New(t)
+
+	cause := errors.New("cause")
+	tg := NewTaskGroup("panic_error", WithCollectRet(), WithDuration(time.Second), WithLog(&memLog{}))
+	tg.AddTaskWithDefault(TaskFunc(func() (interface{}, error) { panic(cause) }), nil)
+	tg.AddTaskWithDefault(TaskFunc(func() (interface{}, error) { panic(42) }), nil)
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Len(ret, 2)
+
+	for _, r := range ret {
+		var pe *PanicError
+		as.ErrorAs(r.OriginalError, &pe)
+		as.NotEmpty(pe.Stack)
+		if pe.Value == 42 {
+			as.EqualError(pe, "42")
+			as.Nil(pe.Unwrap())
+		} else {
+			as.ErrorIs(r.OriginalError, cause)
+		}
+	}
+}
+
+func TestAllowEmpty(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("allow_empty", WithCollectRet(), WithDuration(time.Second), WithAllowEmpty())
+	grs := <-tg.ExecChan()
+	as.Equal(GroupResult{}, grs)
+
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Nil(ret)
+
+	_, complete, err := tg.ExecuteDeadline(context.Background())
+	as.NoError(err)
+	as.True(complete)
+
+	_, err = NewTaskGroup("empty").Execute()
+	as.ErrorIs(err, ErrNoTasks)
+}
+
+func TestEarlyResult(t *testing.T) {
+	as := assert.New(t)
+
+	sum := func(acc interface{}, r Result) interface{} { return acc.(int) + r.Value.(int) }
+	tg := NewTaskGroup("early", WithCollectRet(), WithRunID("req-1"), WithAggregator(sum, 0))
+	tg.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+	tg.AddTaskFunc(func() (interface{}, error) { return 2, nil })
+
+	// 收集结果但没有等待时长，配置错误
+	grs := <-tg.ExecChan()
+	as.ErrorIs(grs.Error, ErrNoTimeoutForCollect)
+	as.Empty(grs.Results)
+	as.Equal(Stats{Total: 2}, grs.Stats)
+	as.Equal(0, grs.Aggregate)
+	as.Equal("req-1", grs.RunID)
+
+	empty := NewTaskGroup("early_empty", WithAllowEmpty(), WithRunID(""))
+	grs = <-empty.ExecChan()
+	as.NoError(grs.Error)
+	as.Equal(Stats{}, grs.Stats)
+	as.NotEmpty(grs.RunID) // 空任务组同样生成 RunID
+}
@@ -0,0 +1,56 @@
+package job
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// WithSummaryLog 每次执行结束时输出一条 Info 日志 "task group summary"，带组名、run_id（设置了 WithRunID 时）、
+// total、succeeded、failed、skipped、panicked、timed_out 和 duration，统一记录执行概况；
+// 有收集方的执行（Execute、ExecChan 等）在收集结束时按 GroupResult.Stats 输出，
+// 没有收集方的执行（无等待时长的异步执行、ExecuteAsync、RunInErrGroup）在所有任务结束时按任务的实际结果输出
+func WithSummaryLog() Option {
+	return summaryLogOption(true)
+}
+
+type summaryLogOption bool
+
+func (s summaryLogOption) bind(o *options) {
+	o.SummaryLog = bool(s)
+}
+
+// selfSummary 返回是否由任务自己在全部结束时输出汇总日志，即没有收集方
+func (ex *execution) selfSummary() bool {
+	return ex.summary && !ex.hasCollector()
+}
+
+// countOutcome 没有收集方时统计任务 i 的最终状态，用于汇总日志
+func (ex *execution) countOutcome(s TaskStatus) {
+	atomic.AddInt64(&ex.outcomes[s], 1)
+}
+
+// outcomeStats 没有收集方时按任务的实际结果得到统计
+func (ex *execution) outcomeStats() Stats {
+	return Stats{
+		Total:     ex.total,
+		Succeeded: int(atomic.LoadInt64(&ex.outcomes[StatusSucceeded])),
+		Failed:    int(atomic.LoadInt64(&ex.outcomes[StatusFailed])),
+		Skipped:   int(atomic.LoadInt64(&ex.outcomes[StatusSkipped])),
+		Panicked:  int(atomic.LoadInt64(&ex.outcomes[StatusPanicked])),
+		TimedOut:  int(atomic.LoadInt64(&ex.outcomes[StatusTimedOut])),
+		Duration:  time.Since(ex.start),
+	}
+}
+
+// logSummary 输出汇总日志，见 WithSummaryLog
+func (ex *execution) logSummary(s Stats) {
+	ex.logInfo("task group summary", map[string]interface{}{
+		"total":     s.Total,
+		"succeeded": s.Succeeded,
+		"failed":    s.Failed,
+		"skipped":   s.Skipped,
+		"panicked":  s.Panicked,
+		"timed_out": s.TimedOut,
+		"duration":  s.Duration.String(),
+	})
+}
@@ -0,0 +1,52 @@
+package job
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummaryLog(t *testing.T) {
+	as := assert.New(t)
+
+	log := &memLog{}
+	tg := NewTaskGroup("summary", WithDuration(50*time.Millisecond), WithCollectRet(), WithLog(log),
+		WithSummaryLog(), WithRunID("run-1"))
+	tg.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, errors.New("failed") })
+	tg.AddTask(newTestSt("slow", 200*time.Millisecond, true))
+
+	_, err := tg.Execute()
+	as.NoError(err)
+	as.Equal(1, log.infoCount("task group summary"))
+	data := log.infoData("task group summary")
+	as.Equal("summary", data["name"])
+	as.Equal("run-1", data["run_id"])
+	as.Equal(3, data["total"])
+	as.Equal(1, data["succeeded"])
+	as.Equal(1, data["failed"])
+	as.Equal(1, data["timed_out"])
+	as.NotEmpty(data["duration"])
+	time.Sleep(200 * time.Millisecond)
+}
+
+func TestSummaryLogAsync(t *testing.T) {
+	as := assert.New(t)
+
+	log := &memLog{}
+	tg := NewTaskGroup("summary_async", WithLog(log), WithSummaryLog())
+	tg.AddTaskFunc(func() (interface{}, error) { return 1, nil })
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, errors.New("failed") })
+	tg.AddTaskFunc(func() (interface{}, error) { return nil, Skip() })
+
+	_, err := tg.Execute()
+	as.NoError(err)
+	as.Eventually(func() bool { return log.infoCount("task group summary") == 1 }, time.Second, 5*time.Millisecond)
+	data := log.infoData("task group summary")
+	as.Equal(3, data["total"])
+	as.Equal(1, data["succeeded"])
+	as.Equal(1, data["failed"])
+	as.Equal(1, data["skipped"])
+}
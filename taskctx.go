@@ -0,0 +1,54 @@
+package job
+
+import (
+	"context"
+	"time"
+)
+
+// AddTaskWithContext 添加带自己上下文的任务，用于把多个独立请求的操作合并到一次执行中
+// 任务以组上下文与 ctx 合并后的上下文执行：任一方取消（或到达截止时间）任务上下文都会被取消，
+// 由 ctx 引起时 context.Cause 为 ctx 的原因；取值时先查组上下文，找不到再查 ctx
+// 任务结束后合并关系随任务上下文一起释放，不会泄漏
+func (tg *Group) AddTaskWithContext(ctx context.Context, t ContextTasker) {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+
+	if tg.taskCtxs == nil {
+		tg.taskCtxs = make(map[int]context.Context)
+	}
+	tg.taskCtxs[len(tg.tasks)] = ctx
+	tg.tasks = append(tg.tasks, t)
+}
+
+// mergedContext 取消信号来自组上下文派生的 Context，取值时回退到任务自己的上下文，截止时间取两者中较早的
+type mergedContext struct {
+	context.Context
+	own context.Context
+}
+
+func (c mergedContext) Value(key any) any {
+	if v := c.Context.Value(key); v != nil {
+		return v
+	}
+	return c.own.Value(key)
+}
+
+// Deadline 返回两个上下文中较早的截止时间
+func (c mergedContext) Deadline() (time.Time, bool) {
+	deadline, ok := c.Context.Deadline()
+	if own, ownOk := c.own.Deadline(); ownOk && (!ok || own.Before(deadline)) {
+		return own, true
+	}
+	return deadline, ok
+}
+
+// mergeContext 将 own 的取消合并到任务上下文 ctx，返回的取消函数同时解除合并
+func mergeContext(ctx context.Context, cancel context.CancelFunc, own context.Context) (context.Context, context.CancelFunc) {
+	merged, cancelCause := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(own, func() { cancelCause(context.Cause(own)) })
+	return mergedContext{Context: merged, own: own}, func() {
+		stop()
+		cancelCause(context.Canceled)
+		cancel()
+	}
+}
@@ -0,0 +1,92 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ctxTimeoutSt 等待上下文结束，超时处理记录结果和错误
+type ctxTimeoutSt struct {
+	key      interface{}
+	timedOut chan Result
+}
+
+func (s *ctxTimeoutSt) Execute() (interface{}, error) {
+	return s.ExecuteCtx(context.Background())
+}
+
+func (s *ctxTimeoutSt) ExecuteCtx(ctx context.Context) (interface{}, error) {
+	<-ctx.Done()
+	return ctx.Value(s.key), context.Cause(ctx)
+}
+
+func (s *ctxTimeoutSt) TimeoutHandler(ret interface{}, err error) {
+	s.timedOut <- Result{Value: ret, Error: err}
+}
+
+func TestAddTaskWithContext(t *testing.T) {
+	as := assert.New(t)
+
+	type reqKey struct{}
+	stopped := errors.New("request cancelled")
+	reqCtx, cancelReq := context.WithCancelCause(context.WithValue(context.Background(), reqKey{}, "req-1"))
+
+	tg := NewTaskGroup("task_ctx", WithCollectRet(), WithDuration(time.Second))
+	task := &ctxTimeoutSt{key: reqKey{}, timedOut: make(chan Result, 1)}
+	tg.AddTaskWithContext(reqCtx, task)
+	tg.AddTaskWithContext(context.Background(), TaskFuncCtx(func(ctx context.Context) (interface{}, error) {
+		return "other", ctx.Err()
+	}))
+
+	ch := tg.ExecChan()
+	time.Sleep(10 * time.Millisecond)
+	cancelReq(stopped) // 只取消第一个任务所属的请求
+	grs := <-ch
+	as.NoError(grs.Error)
+	as.Equal([]Result{{Value: "other"}}, grs.Results)
+	as.Equal(Result{Value: "req-1", Error: stopped}, <-task.timedOut) // 能取到请求上下文的值和取消原因
+
+	// 组上下文取消同样取消任务
+	tg.Reset()
+	tg.AddTaskWithContext(context.Background(), TaskFuncCtx(func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}))
+	tg.WithContext(reqCtx)
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Empty(ret)
+}
+
+func TestAddTaskWithContextDeadline(t *testing.T) {
+	as := assert.New(t)
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	reqDeadline, _ := reqCtx.Deadline()
+
+	deadline := func(ctx context.Context) (interface{}, error) {
+		d, ok := ctx.Deadline()
+		if !ok {
+			return nil, errors.New("no deadline")
+		}
+		return d, nil
+	}
+	tg := NewTaskGroup("task_ctx_deadline", WithCollectRet(), WithDuration(time.Second))
+	tg.AddTaskWithContext(reqCtx, TaskFuncCtx(deadline))
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Equal([]Result{{Value: reqDeadline}}, ret) // 请求的截止时间更早
+
+	tg.Reset()
+	tg.AddTaskWithContext(context.Background(), TaskFuncCtx(deadline))
+	start := time.Now()
+	ret, err = tg.Execute()
+	as.NoError(err)
+	as.Len(ret, 1)
+	as.WithinDuration(start.Add(time.Second), ret[0].Value.(time.Time), 100*time.Millisecond) // 只有组的截止时间
+}
@@ -0,0 +1,102 @@
+package job
+
+// TypedTasker 返回确定类型结果的任务
+type TypedTasker[T any] interface {
+	Execute() (T, error)
+}
+
+// TypedTaskTimeout 类型化的超时处理，任务超时前没有产生结果时 ret 为 T 的零值
+type TypedTaskTimeout[T any] interface {
+	OnTimeout(ret T, err error)
+}
+
+// TypedTaskFunc 函数形式的类型化任务
+type TypedTaskFunc[T any] func() (T, error)
+
+func (f TypedTaskFunc[T]) Execute() (T, error) {
+	return f()
+}
+
+// TypedResult 类型化的任务结果
+type TypedResult[T any] struct {
+	Value  T
+	Error  error
+	Status TaskStatus
+}
+
+// TypedGroup 结果类型确定的任务组，执行语义与 Group 相同
+type TypedGroup[T any] struct {
+	group *Group
+}
+
+// NewTypedGroup 创建类型化任务组，选项与 NewTaskGroup 相同
+func NewTypedGroup[T any](name string, opts ...Option) *TypedGroup[T] {
+	return &TypedGroup[T]{group: NewTaskGroup(name, opts...)}
+}
+
+// Group 返回底层的任务组，用于设置名称、取消任务等
+func (tg *TypedGroup[T]) Group() *Group {
+	return tg.group
+}
+
+// AddTask 添加任务，任务实现 TypedTaskTimeout[T] 时超时以类型化的结果调用 OnTimeout
+func (tg *TypedGroup[T]) AddTask(t TypedTasker[T]) {
+	tg.group.AddTask(typedTask[T]{t})
+}
+
+// AddTaskFunc 添加函数任务
+func (tg *TypedGroup[T]) AddTaskFunc(fn func() (T, error)) {
+	tg.AddTask(TypedTaskFunc[T](fn))
+}
+
+// Reset 清空任务
+func (tg *TypedGroup[T]) Reset() {
+	tg.group.Reset()
+}
+
+// Execute 执行所有任务，结果与 Group.Execute 一致，Value 转为 T
+func (tg *TypedGroup[T]) Execute() ([]TypedResult[T], error) {
+	results, err := tg.group.Execute()
+	if results == nil {
+		return nil, err
+	}
+	return typedResults[T](results), err
+}
+
+// ExecuteFixed 执行所有任务，按任务下标返回类型化结果，语义同 Group.ExecuteFixed
+func (tg *TypedGroup[T]) ExecuteFixed() ([]TypedResult[T], error) {
+	results, err := tg.group.ExecuteFixed()
+	if results == nil {
+		return nil, err
+	}
+	return typedResults[T](results), err
+}
+
+// typedResults 将结果的 Value 转为 T，类型不符时为 T 的零值
+func typedResults[T any](results []Result) []TypedResult[T] {
+	typed := make([]TypedResult[T], 0, len(results))
+	for _, r := range results {
+		v, _ := r.Value.(T)
+		typed = append(typed, TypedResult[T]{Value: v, Error: r.Error, Status: r.Status})
+	}
+	return typed
+}
+
+// typedTask 将 TypedTasker 适配为 Tasker
+type typedTask[T any] struct {
+	task TypedTasker[T]
+}
+
+func (t typedTask[T]) Execute() (interface{}, error) {
+	return t.task.Execute()
+}
+
+// TimeoutHandler 转发给 TypedTaskTimeout，没有产生结果时传入 T 的零值
+func (t typedTask[T]) TimeoutHandler(ret interface{}, err error) {
+	h, ok := t.task.(TypedTaskTimeout[T])
+	if !ok {
+		return
+	}
+	v, _ := ret.(T)
+	h.OnTimeout(v, err)
+}
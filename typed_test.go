@@ -0,0 +1,93 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type typedTimeoutSt struct {
+	duration time.Duration
+	timedOut chan int
+}
+
+func (s *typedTimeoutSt) Execute() (int, error) {
+	time.Sleep(s.duration)
+	return 42, nil
+}
+
+func (s *typedTimeoutSt) OnTimeout(ret int, err error) {
+	s.timedOut <- ret
+}
+
+func TestTypedGroup(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTypedGroup[int]("typed", WithCollectRet(), WithDuration(30*time.Millisecond))
+	tg.AddTaskFunc(func() (int, error) { return 1, nil })
+	slow := &typedTimeoutSt{duration: 100 * time.Millisecond, timedOut: make(chan int, 1)}
+	tg.AddTask(slow)
+	ret, err := tg.Execute()
+	as.NoError(err)
+	as.Equal([]TypedResult[int]{{Value: 1}}, ret)
+	as.Equal(42, <-slow.timedOut)
+
+	// 超时前没有产生结果时传入零值
+	tg = NewTypedGroup[int]("typed_gate", WithDuration(20*time.Millisecond), WithStartGate(make(chan struct{})))
+	gated := &typedTimeoutSt{timedOut: make(chan int, 1)}
+	tg.AddTask(gated)
+	_, err = tg.Execute()
+	as.NoError(err)
+	as.Equal(0, <-gated.timedOut)
+}
+
+func TestTypedExecuteFixed(t *testing.T) {
+	as := assert.New(t)
+
+	failed := errors.New("failed")
+	tg := NewTypedGroup[int]("typed_fixed", WithDuration(30*time.Millisecond))
+	tg.AddTaskFunc(func() (int, error) {
+		time.Sleep(10 * time.Millisecond)
+		return 1, nil
+	})
+	tg.AddTask(&typedTimeoutSt{duration: 100 * time.Millisecond, timedOut: make(chan int, 1)})
+	tg.AddTaskFunc(func() (int, error) { return 0, failed })
+	tg.AddTaskFunc(func() (int, error) { return 3, nil })
+
+	ret, err := tg.ExecuteFixed()
+	as.NoError(err)
+	if as.Len(ret, 4) {
+		as.Equal(TypedResult[int]{Value: 1}, ret[0])
+		as.Equal(StatusTimedOut, ret[1].Status)
+		as.ErrorIs(ret[1].Error, context.DeadlineExceeded)
+		as.Equal(TypedResult[int]{Error: failed, Status: StatusFailed}, ret[2])
+		as.Equal(TypedResult[int]{Value: 3}, ret[3])
+	}
+
+	empty := NewTypedGroup[int]("typed_fixed_empty")
+	_, err = empty.ExecuteFixed()
+	as.ErrorIs(err, ErrNoTasks)
+}
+
+func TestTypedExecuteFixedPanic(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTypedGroup[int]("typed_fixed_panic", WithDuration(30*time.Millisecond), WithLog(&memLog{}))
+	tg.AddTaskFunc(func() (int, error) { panic("boom") })
+	tg.AddTask(&typedTimeoutSt{duration: 100 * time.Millisecond, timedOut: make(chan int, 1)})
+
+	ret, err := tg.ExecuteFixed()
+	as.NoError(err)
+	if as.Len(ret, 2) {
+		as.Equal(StatusPanicked, ret[0].Status) // panic 的任务不算超时
+		var panicErr *PanicError
+		if as.ErrorAs(ret[0].Error, &panicErr) {
+			as.Equal("boom", panicErr.Value)
+		}
+		as.Equal(StatusTimedOut, ret[1].Status)
+		as.ErrorIs(ret[1].Error, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,89 @@
+package job
+
+// firstWaiter WaitFirst 等待的前 n 个结果，只在收集协程中写入
+type firstWaiter struct {
+	n       int
+	sent    bool
+	done    chan struct{} // 前 n 个结果就绪或收集结束时关闭
+	results []Result
+	err     error
+	rest    chan GroupResult // 收集结束时的最终结果，缓存为 1
+}
+
+// WaitFirst 执行所有任务，收集到前 n 个结果后立即按完成顺序返回它们，其余任务在后台继续执行，
+// 收集照常进行直到所有任务结束或等待时长到达：sink、兜底值、超时处理和 WithDefer 的语义不变，
+// 本次执行的最终 GroupResult（包含全部结果，也包括已返回的前 n 个）通过 Remaining 取得
+// 结果按 WithResultFilter、WithResultDedup 过滤后计数；收集结束时仍不足 n 个则返回已收集的全部结果和组的错误；
+// n <= 0 时立即返回空结果；未设置等待时长时等待所有任务结束
+func (tg *Group) WaitFirst(n int) ([]Result, error) {
+	tg.mu.Lock()
+	if len(tg.tasks) == 0 {
+		tg.runEarlyDefers()
+		tg.mu.Unlock()
+		if tg.allowEmpty {
+			return nil, nil
+		}
+		return nil, tg.configError(ErrNoTasks)
+	}
+	if err := tg.checkRun(); err != nil {
+		tg.runEarlyDefers()
+		tg.mu.Unlock()
+		return nil, err
+	}
+
+	ex := tg.newExecution(tg.ctx)
+	ex.collect = true
+	ex.first = &firstWaiter{n: n, done: make(chan struct{}), rest: make(chan GroupResult, 1)}
+	if n <= 0 {
+		ex.first.sent = true
+		close(ex.first.done)
+	}
+	tg.run(ex)
+	tg.mu.Unlock()
+
+	go func() {
+		defer ex.cancel()
+		ex.first.finish(tg.groupResult(ex, tg.collectResults(ex)))
+	}()
+
+	<-ex.first.done
+	return ex.first.results, ex.first.err
+}
+
+// Remaining 返回最近一次 WaitFirst 的最终结果通道，收集结束时收到一个 GroupResult 后关闭，可用于等待后台任务；
+// 最近一次执行不是 WaitFirst 或没有执行过时返回 nil
+func (tg *Group) Remaining() <-chan GroupResult {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+
+	if tg.cur == nil || tg.cur.first == nil {
+		return nil
+	}
+	return tg.cur.first.rest
+}
+
+// reachFirst 收集到第 n 个结果时交给 WaitFirst
+func (ex *execution) reachFirst(results []Result) {
+	f := ex.first
+	if f == nil || f.sent || len(results) < f.n {
+		return
+	}
+	f.sent = true
+	f.results = append([]Result(nil), results[:f.n]...)
+	close(f.done)
+}
+
+// finish 收集结束，还没交给 WaitFirst 时交出前 n 个结果，不足 n 个时交出全部结果和组的错误
+func (f *firstWaiter) finish(grs GroupResult) {
+	if !f.sent {
+		f.sent = true
+		if len(grs.Results) >= f.n {
+			f.results = append([]Result(nil), grs.Results[:f.n]...)
+		} else {
+			f.results, f.err = grs.Results, grs.Error
+		}
+		close(f.done)
+	}
+	f.rest <- grs
+	close(f.rest)
+}
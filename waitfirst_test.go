@@ -0,0 +1,55 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitFirst(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("wait_first", WithDuration(time.Second))
+	tg.AddTask(newTestSt("slow", 100*time.Millisecond, true))
+	tg.AddTask(newTestSt("fast", 0, true))
+	tg.AddTask(newTestSt("medium", 20*time.Millisecond, true))
+
+	start := time.Now()
+	ret, err := tg.WaitFirst(2)
+	as.NoError(err)
+	as.Less(time.Since(start), 100*time.Millisecond)
+	as.Equal([]Result{{Value: "fast"}, {Value: "medium"}}, ret) // 按完成顺序
+
+	// 其余任务在后台继续执行
+	grs := <-tg.Remaining()
+	as.NoError(grs.Error)
+	as.Len(grs.Results, 3)
+	as.Equal(Result{Value: "slow"}, grs.Results[2])
+	as.Equal(3, grs.Stats.Succeeded)
+	_, ok := <-tg.Remaining()
+	as.False(ok)
+}
+
+func TestWaitFirstShort(t *testing.T) {
+	as := assert.New(t)
+
+	tg := NewTaskGroup("wait_first_short", WithDuration(30*time.Millisecond), WithRequireAnySuccess())
+	tg.AddTask(newTestSt("timeout", 100*time.Millisecond, true))
+	tg.AddTask(newTestSt("timeout2", 100*time.Millisecond, true))
+
+	// 收集结束时仍不足 n 个，返回全部结果和组的错误
+	ret, err := tg.WaitFirst(1)
+	as.ErrorIs(err, ErrAllFailed)
+	as.Empty(ret)
+	grs := <-tg.Remaining()
+	as.Equal(2, grs.Stats.TimedOut)
+
+	ret, err = tg.WaitFirst(0)
+	as.NoError(err)
+	as.Empty(ret)
+	<-tg.Remaining()
+
+	as.Nil(NewTaskGroup("none").Remaining())
+	time.Sleep(100 * time.Millisecond)
+}